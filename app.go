@@ -0,0 +1,350 @@
+package gluon
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-gluon/gluon/config"
+	"github.com/go-gluon/gluon/log"
+)
+
+// StrictFlagsProperty property which enables rejecting of the unknown command line flags
+const StrictFlagsProperty = "gluon.config.flags.strict"
+
+// LogCategoryProperty prefix of the category levels `gluon.log.category."<name>".level`
+const LogCategoryProperty = "gluon.log.category"
+
+// Option application builder option
+type Option func(a *App)
+
+// WithConfig use the configuration source provider instead of creating a new one
+func WithConfig(c *config.ConfigSourceProvider) Option {
+	return func(a *App) {
+		a.config = c
+	}
+}
+
+// WithLogger use the logger instead of the global log.Log. The levels of the `gluon.log` configuration
+// are applied to the logger by the application instead of the global levels.
+func WithLogger(logger log.Logger) Option {
+	return func(a *App) {
+		a.logger = logger
+	}
+}
+
+// WithArgs command line arguments without the program name, nil means os.Args[1:]
+func WithArgs(args []string) Option {
+	return func(a *App) {
+		a.args = args
+	}
+}
+
+// WithEnviron environment variables in the form "key=value", nil means os.Environ()
+func WithEnviron(environ []string) Option {
+	return func(a *App) {
+		a.environ = environ
+	}
+}
+
+// WithResources application resources with the application.yaml file
+func WithResources(resources embed.FS) Option {
+	return func(a *App) {
+		a.resources = resources
+		a.hasResources = true
+	}
+}
+
+// WithExtensions extensions providers of the application
+func WithExtensions(providers ...ExtensionProvider) Option {
+	return func(a *App) {
+		a.providers = append(a.providers, providers...)
+	}
+}
+
+// WithOutput output of the usage, default os.Stderr
+func WithOutput(w io.Writer) Option {
+	return func(a *App) {
+		a.output = w
+	}
+}
+
+// App gluon application
+type App struct {
+	config       *config.ConfigSourceProvider
+	logger       log.Logger
+	output       io.Writer
+	args         []string
+	environ      []string
+	resources    embed.FS
+	hasResources bool
+	providers    []ExtensionProvider
+	extensions   []Extension
+	commands     []Command
+	command      *commandPath
+	globalLogger bool
+	// installed the global logger was installed by the configuration of the application
+	installed bool
+	levels    *log.Levels
+}
+
+// New create application. Without the configuration option a new configuration source
+// provider is created from the arguments and environment variables.
+func New(opts ...Option) (*App, error) {
+	a := &App{}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.logger == nil {
+		a.logger = log.Log
+		a.globalLogger = true
+	} else {
+		a.levels = log.NewLevels(log.GetLevel())
+		a.logger = log.NewLevelLogger(a.logger, a.levels)
+	}
+	if a.output == nil {
+		a.output = os.Stderr
+	}
+	if a.config == nil {
+		c, err := config.NewProvider(a.args, a.environ)
+		if err != nil {
+			return nil, err
+		}
+		if !a.globalLogger {
+			c.SetLogger(a.logger)
+		}
+		a.config = c
+	}
+	return a, nil
+}
+
+// Config configuration source provider of the application
+func (a *App) Config() *config.ConfigSourceProvider {
+	return a.config
+}
+
+// Logger of the application
+func (a *App) Logger() log.Logger {
+	return a.logger
+}
+
+// Resources of the application
+func (a *App) Resources() embed.FS {
+	return a.resources
+}
+
+// ExtensionLogger returns the logger of the application named after the extension
+// with the `extension` field
+func (a *App) ExtensionLogger(name string) log.Logger {
+	fields := log.Fields{"extension": name}
+	if a.globalLogger {
+		return log.Named(name).With(fields)
+	}
+	return log.From(a.logger).Named(name).With(fields)
+}
+
+// Extensions started extensions of the application sorted by the priority
+func (a *App) Extensions() []Extension {
+	return a.extensions
+}
+
+// Start load the resources configuration and initialize the extensions. The application is closed
+// when the start fails, so the buffered log entries are written.
+func (a *App) Start() error {
+	err := a.start()
+	if err != nil {
+		_ = a.Close()
+	}
+	return err
+}
+
+func (a *App) start() error {
+	// core modules
+	if a.hasResources {
+		err := a.config.AddYaml(a.resources)
+		if err != nil {
+			return err
+		}
+	}
+	err := a.configureLog()
+	if err != nil {
+		return err
+	}
+
+	extensions := make([]Extension, len(a.providers))
+	for i, e := range a.providers {
+		extensions[i] = e.NewExtesion()
+		if extensions[i].Init != nil && extensions[i].InitLogger != nil {
+			return fmt.Errorf("extension %s has both the Init and the InitLogger function", extensions[i].Name)
+		}
+	}
+
+	// sort extension base on the priority
+	sort.Slice(extensions, func(i, j int) bool {
+		return extensions[i].Priority < extensions[j].Priority
+	})
+
+	// configuration types and properties are available before the extensions are initialized
+	for _, e := range extensions {
+		err := a.config.RegisterTypes(e.Types...)
+		if err != nil {
+			return err
+		}
+	}
+	for _, e := range extensions {
+		if e.Config != nil {
+			items, err := a.config.DescribeExtension(e.Name, e.Config)
+			if err != nil {
+				return err
+			}
+			a.config.RegisterProperties(items...)
+		}
+		err := a.addCommands(e)
+		if err != nil {
+			return err
+		}
+	}
+	err = a.selectCommand()
+	if err != nil {
+		return err
+	}
+	err = a.checkFlags()
+	if err != nil {
+		return err
+	}
+
+	// activate extension
+	if len(extensions) > 0 {
+
+		tmp := make([]string, len(extensions))
+		for i, e := range extensions {
+			tmp[i] = e.Name
+
+			if e.Config != nil {
+				err := a.config.Extension(e.Name, e.Config)
+				if err != nil {
+					return err
+				}
+			}
+
+			if e.Init != nil {
+				err := e.Init(a.resources, e.Config)
+				if err != nil {
+					return err
+				}
+			} else if e.InitLogger != nil {
+				err := e.InitLogger(a.resources, e.Config, a.ExtensionLogger(e.Name))
+				if err != nil {
+					return err
+				}
+			}
+			a.extensions = append(a.extensions, e)
+		}
+		a.logger.Info("Loaded extension", log.Fields{"extensions": tmp})
+	}
+	// the structures bound by the extensions are known flags
+	return a.checkUnknownFlags()
+}
+
+// configureLog bind the `gluon.log` configuration and apply it to the global logging when the
+// application uses the global logger, otherwise only the levels are applied to the application logger
+func (a *App) configureLog() error {
+	cfg := log.NewConfig()
+	err := a.config.Extension("log", &cfg)
+	if err != nil {
+		return err
+	}
+	for _, name := range cfg.Outputs {
+		output := log.OutputConfig{}
+		err = a.config.Extension("log.output."+name, &output)
+		if err != nil {
+			return err
+		}
+		cfg.Output[name] = output
+	}
+	items, err := a.config.PropertyMapE(LogCategoryProperty, nil)
+	if err != nil {
+		return err
+	}
+	for key, value := range items {
+		if strings.HasSuffix(key, ".level") {
+			cfg.Categories[strings.Trim(strings.TrimSuffix(key, ".level"), `"`)] = value
+		}
+	}
+	if !a.globalLogger {
+		return a.levels.Configure(cfg)
+	}
+	previous := log.Log
+	err = log.Configure(cfg)
+	if err != nil {
+		return err
+	}
+	// the format may replace the global logger
+	a.logger = log.Log
+	a.installed = a.logger != previous
+	return nil
+}
+
+// Close write the buffered log entries. The global logger installed by the application is closed
+// and the previous global logger is restored, the other loggers are only flushed.
+func (a *App) Close() error {
+	if a.globalLogger && a.installed && log.Log == a.logger {
+		err := log.Reset()
+		a.logger = log.Log
+		a.installed = false
+		return err
+	}
+	if f, ok := a.logger.(log.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// checkFlags print the usage for the help flag and validate the values of the known command line
+// flags. Returns flag.ErrHelp after the usage is printed.
+func (a *App) checkFlags() error {
+	for _, f := range a.flagSources() {
+		if f.Help() {
+			f.Usage(a.output)
+			a.commandsUsage(a.output, a.command)
+			return flag.ErrHelp
+		}
+		err := f.ValidateValues()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUnknownFlags rejects the unknown command line flags when the `gluon.config.flags.strict`
+// property is true. The check is done after the extensions are initialized, so the flags of the
+// structures bound by the extensions are known.
+func (a *App) checkUnknownFlags() error {
+	strict := a.config.PropertyBool(StrictFlagsProperty, false)
+	for _, f := range a.flagSources() {
+		if strict {
+			f.Strict = true
+		}
+		err := f.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flagSources command line flags configuration sources of the application
+func (a *App) flagSources() []*config.FlagsConfigSource {
+	result := []*config.FlagsConfigSource{}
+	for _, s := range a.config.Sources() {
+		if f, ok := s.(*config.FlagsConfigSource); ok {
+			result = append(result, f)
+		}
+	}
+	return result
+}
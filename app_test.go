@@ -0,0 +1,412 @@
+package gluon
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/go-gluon/gluon/config"
+	"github.com/go-gluon/gluon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed testdata
+var resources embed.FS
+
+type sampleConfig struct {
+	Name string `config:"name"`
+	Port int    `config:"port"`
+}
+
+type sampleProvider struct {
+	config *sampleConfig
+	inits  int
+}
+
+func (p *sampleProvider) NewExtesion() Extension {
+	return Extension{
+		Name:   "sample",
+		Config: p.config,
+		Init: func(resources embed.FS, config interface{}) error {
+			p.inits++
+			return nil
+		},
+	}
+}
+
+type testLogger struct {
+	messages []string
+	fields   []log.Fields
+}
+
+func (l *testLogger) Trace(msg string, fields ...map[string]interface{}) { l.add(msg, fields) }
+func (l *testLogger) Debug(msg string, fields ...map[string]interface{}) { l.add(msg, fields) }
+func (l *testLogger) Info(msg string, fields ...map[string]interface{})  { l.add(msg, fields) }
+func (l *testLogger) Warn(msg string, fields ...map[string]interface{})  { l.add(msg, fields) }
+func (l *testLogger) Error(msg string, fields ...map[string]interface{}) { l.add(msg, fields) }
+
+func (l *testLogger) add(msg string, fields []map[string]interface{}) {
+	l.messages = append(l.messages, msg)
+	l.fields = append(l.fields, log.MergeFields(fields...))
+}
+
+var _ log.Logger = &testLogger{}
+
+func TestIsolatedApps(t *testing.T) {
+	p1 := &sampleProvider{config: &sampleConfig{}}
+	l1 := &testLogger{}
+	a1, err := New(
+		WithArgs([]string{"--gluon-sample-port=9090"}),
+		WithEnviron([]string{}),
+		WithLogger(l1),
+		WithResources(resources),
+		WithExtensions(p1),
+	)
+	assert.Nil(t, err)
+
+	p2 := &sampleProvider{config: &sampleConfig{}}
+	a2, err := New(
+		WithArgs([]string{}),
+		WithEnviron([]string{"GLUON_SAMPLE_NAME=from-env"}),
+		WithLogger(&testLogger{}),
+		WithResources(resources),
+		WithExtensions(p2),
+	)
+	assert.Nil(t, err)
+	assert.NotSame(t, a1.Config(), a2.Config())
+	assert.NotSame(t, config.Default, a1.Config())
+
+	assert.Nil(t, a1.Start())
+	assert.Nil(t, a2.Start())
+
+	assert.Equal(t, "from-yaml", p1.config.Name)
+	assert.Equal(t, 9090, p1.config.Port)
+	assert.Equal(t, "from-env", p2.config.Name)
+	assert.Equal(t, 8080, p2.config.Port)
+	assert.Equal(t, 1, p1.inits)
+	assert.Equal(t, []string{"Loaded extension"}, l1.messages)
+	assert.Equal(t, 1, len(a1.Extensions()))
+	assert.Equal(t, "NO_VALUE", config.Property("gluon.sample.name", "NO_VALUE"))
+}
+
+func TestAppWithConfig(t *testing.T) {
+	c := &config.ConfigSourceProvider{}
+	a, err := New(WithConfig(c))
+	assert.Nil(t, err)
+	assert.Same(t, c, a.Config())
+	assert.Equal(t, log.Log, a.Logger())
+	assert.Nil(t, a.Start())
+}
+
+func TestAppFlags(t *testing.T) {
+	b := &strings.Builder{}
+	a, err := New(
+		WithArgs([]string{"--help"}),
+		WithEnviron([]string{}),
+		WithLogger(&testLogger{}),
+		WithOutput(b),
+		WithResources(resources),
+		WithExtensions(&sampleProvider{config: &sampleConfig{}}),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, flag.ErrHelp, a.Start())
+	assert.Contains(t, b.String(), "  --gluon-sample-port int\n")
+
+	p := &sampleProvider{config: &sampleConfig{}}
+	a, err = New(
+		WithArgs([]string{"--gluon-sample-prot=1"}),
+		WithEnviron([]string{"GLUON_CONFIG_FLAGS_STRICT=true"}),
+		WithLogger(&testLogger{}),
+		WithResources(resources),
+		WithExtensions(p),
+	)
+	assert.Nil(t, err)
+	assert.EqualError(t, a.Start(), "unknown flag: --gluon-sample-prot")
+
+	// the structures bound by the extension Init function are known flags
+	c, err := config.NewProvider([]string{"--gluon-late-value=5"}, []string{"GLUON_CONFIG_FLAGS_STRICT=true"})
+	assert.Nil(t, err)
+	late := &lateProvider{c: c}
+	a, err = New(WithConfig(c), WithLogger(&testLogger{}), WithExtensions(late))
+	assert.Nil(t, err)
+	assert.Nil(t, a.Start())
+	assert.Equal(t, 5, late.config.Value)
+
+	p = &sampleProvider{config: &sampleConfig{}}
+	a, err = New(
+		WithArgs([]string{"--gluon-sample-port=abc"}),
+		WithEnviron([]string{}),
+		WithLogger(&testLogger{}),
+		WithExtensions(p),
+	)
+	assert.Nil(t, err)
+	assert.EqualError(t, a.Start(), `invalid value "abc" for flag --gluon-sample-port: strconv.ParseInt: parsing "abc": invalid syntax`)
+	assert.Equal(t, 0, p.inits)
+}
+
+type lateConfig struct {
+	Value int `config:"value"`
+}
+
+// lateProvider binds the configuration in the Init function
+type lateProvider struct {
+	c      *config.ConfigSourceProvider
+	config lateConfig
+}
+
+func (p *lateProvider) NewExtesion() Extension {
+	return Extension{
+		Name: "late",
+		Init: func(resources embed.FS, config interface{}) error {
+			return p.c.Extension("late", &p.config)
+		},
+	}
+}
+
+type migrateConfig struct {
+	Steps  int  `config:"steps"`
+	DryRun bool `config:"dry-run"`
+}
+
+type commandProvider struct {
+	runs    []string
+	config  *migrateConfig
+	args    []string
+	started bool
+}
+
+func (p *commandProvider) NewExtesion() Extension {
+	return Extension{
+		Name: "commands",
+		Init: func(resources embed.FS, config interface{}) error {
+			p.started = true
+			return nil
+		},
+		Commands: []Command{
+			{Name: "serve", Usage: "Start the server", Run: func(config interface{}, args []string) error {
+				p.runs = append(p.runs, "serve")
+				p.args = args
+				return nil
+			}},
+			{Name: "migrate", Usage: "Database migrations", Commands: []Command{
+				{Name: "up", Config: &migrateConfig{Steps: 1}, Run: func(config interface{}, args []string) error {
+					p.runs = append(p.runs, "migrate up")
+					p.config = config.(*migrateConfig)
+					p.args = args
+					return nil
+				}},
+			}},
+		},
+	}
+}
+
+func runCommand(t *testing.T, b *strings.Builder, args ...string) (*commandProvider, error) {
+	p := &commandProvider{}
+	a, err := New(
+		WithArgs(args),
+		WithEnviron([]string{"GLUON_CONFIG_FLAGS_STRICT=true"}),
+		WithLogger(&testLogger{}),
+		WithOutput(b),
+		WithExtensions(p),
+	)
+	assert.Nil(t, err)
+	return p, a.Run()
+}
+
+func TestAppCommands(t *testing.T) {
+	b := &strings.Builder{}
+	p, err := runCommand(t, b, "migrate", "up", "--steps", "3", "--dry-run", "a", "--", "b")
+	assert.Nil(t, err)
+	assert.True(t, p.started)
+	assert.Equal(t, []string{"migrate up"}, p.runs)
+	assert.Equal(t, &migrateConfig{Steps: 3, DryRun: true}, p.config)
+	assert.Equal(t, []string{"a", "b"}, p.args)
+
+	p, err = runCommand(t, b, "serve", "--", "migrate")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"serve"}, p.runs)
+	assert.Equal(t, []string{"migrate"}, p.args)
+
+	_, err = runCommand(t, b, "serve", "--steps=1")
+	assert.EqualError(t, err, "unknown flag: --steps")
+
+	_, err = runCommand(t, b, "deploy")
+	assert.EqualError(t, err, "unknown command: deploy")
+
+	b.Reset()
+	_, err = runCommand(t, b, "migrate")
+	assert.Equal(t, ErrNoCommand, err)
+	assert.Equal(t, "Commands:\n  up\n", b.String())
+
+	b.Reset()
+	_, err = runCommand(t, b, "--help")
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Contains(t, b.String(), "Commands:\n  serve\n    \tStart the server\n  migrate\n    \tDatabase migrations\n")
+
+	b.Reset()
+	_, err = runCommand(t, b, "deploy", "--help")
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Contains(t, b.String(), "Commands:\n  serve\n")
+
+	b.Reset()
+	_, err = runCommand(t, b, "migrate", "up", "--help")
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Contains(t, b.String(), "  --steps int\n    \t(default 1)\n")
+	assert.NotContains(t, b.String(), "(env STEPS)")
+
+	// the command configuration is bound only from the flags
+	p = &commandProvider{}
+	a, err := New(
+		WithArgs([]string{"migrate", "up", "--dry-run"}),
+		WithEnviron([]string{"STEPS=7"}),
+		WithLogger(&testLogger{}),
+		WithOutput(b),
+		WithExtensions(p),
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, a.Run())
+	assert.Equal(t, &migrateConfig{Steps: 1, DryRun: true}, p.config)
+}
+
+func TestAppLogLevels(t *testing.T) {
+	t.Cleanup(func() {
+		log.SetLevel(log.InfoLevel)
+		log.RemoveCategoryLevel("orders.db")
+	})
+	a, err := New(
+		WithArgs([]string{"--gluon-log-level=warn"}),
+		WithEnviron([]string{"GLUON_LOG_CATEGORY_ORDERS_DB_LEVEL=debug"}),
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, a.Start())
+	assert.Equal(t, log.WarnLevel, log.GetLevel())
+	assert.True(t, log.IsCategoryEnabled("orders.db", log.DebugLevel))
+	assert.False(t, log.IsCategoryEnabled("orders", log.DebugLevel))
+
+	l := &testLogger{}
+	a, err = New(
+		WithArgs([]string{"--gluon-log-level=error", "--gluon-log-category-orders-level=debug"}),
+		WithEnviron([]string{}),
+		WithLogger(l),
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, a.Start())
+	assert.Equal(t, log.WarnLevel, log.GetLevel())
+	assert.False(t, log.IsCategoryEnabled("orders", log.DebugLevel))
+
+	a.Logger().Warn("Skipped")
+	a.Logger().Error("Written")
+	a.ExtensionLogger("orders").Debug("Orders")
+	a.ExtensionLogger("payments").Debug("Payments")
+	assert.Equal(t, []string{"Written", "Orders"}, l.messages)
+}
+
+func TestAppLogFormat(t *testing.T) {
+	original := log.Log
+	t.Cleanup(func() {
+		log.Log = original
+	})
+	a, err := New(WithArgs([]string{}), WithEnviron([]string{"GLUON_LOG_FORMAT=json"}))
+	assert.Nil(t, err)
+	assert.Nil(t, a.Start())
+	assert.IsType(t, &log.FormatLogger{}, log.Log)
+	assert.Equal(t, log.Log, a.Logger())
+
+	a, err = New(WithArgs([]string{}), WithEnviron([]string{"GLUON_LOG_FORMAT=json", "GLUON_LOG_SAMPLING_ENABLED=true"}))
+	assert.Nil(t, err)
+	assert.Nil(t, a.Start())
+	assert.IsType(t, &log.SamplingLogger{}, log.Log)
+	assert.Nil(t, a.Close())
+	assert.Equal(t, original, log.Log)
+	assert.Equal(t, log.Log, a.Logger())
+
+	a, err = New(WithArgs([]string{"--gluon-sample-port=x"}), WithEnviron([]string{"GLUON_LOG_ASYNC_ENABLED=true"}),
+		WithExtensions(&sampleProvider{config: &sampleConfig{}}))
+	assert.Nil(t, err)
+	assert.NotNil(t, a.Start())
+	assert.Equal(t, original, log.Log)
+
+	a, err = New(WithArgs([]string{}), WithEnviron([]string{"GLUON_LOG_FORMAT=xml"}))
+	assert.Nil(t, err)
+	assert.EqualError(t, a.Start(), `unknown log format: "xml"`)
+}
+
+type closerLogger struct {
+	testLogger
+	closed bool
+}
+
+func (l *closerLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+type failingProvider struct{}
+
+func (p *failingProvider) NewExtesion() Extension {
+	return Extension{
+		Name: "failing",
+		Init: func(resources embed.FS, config interface{}) error {
+			return errors.New("init failed")
+		},
+	}
+}
+
+func TestAppGlobalLoggerKept(t *testing.T) {
+	original := log.Log
+	l := &closerLogger{}
+	log.Log = l
+	t.Cleanup(func() {
+		log.Log = original
+	})
+	for _, environ := range [][]string{{}, {"GLUON_LOG_ASYNC_ENABLED=true"}} {
+		a, err := New(WithArgs([]string{}), WithEnviron(environ), WithExtensions(&failingProvider{}))
+		assert.Nil(t, err)
+		assert.EqualError(t, a.Start(), "init failed")
+		assert.Same(t, l, log.Log)
+		assert.EqualError(t, a.Run(), "init failed")
+		assert.Same(t, l, log.Log)
+		assert.False(t, l.closed)
+	}
+}
+
+type loggerProvider struct{}
+
+func (p *loggerProvider) NewExtesion() Extension {
+	return Extension{
+		Name: "orders",
+		InitLogger: func(resources embed.FS, config interface{}, logger log.Logger) error {
+			logger.Warn("Orders started", log.Add("count", 1))
+			return nil
+		},
+	}
+}
+
+func TestAppExtensionLogger(t *testing.T) {
+	l := &testLogger{}
+	a, err := New(WithArgs([]string{}), WithEnviron([]string{}), WithLogger(l), WithExtensions(&loggerProvider{}))
+	assert.Nil(t, err)
+	assert.Nil(t, a.Start())
+	assert.Equal(t, []string{"Orders started", "Loaded extension"}, l.messages)
+	assert.Equal(t, log.Fields{"category": "orders", "count": 1, "extension": "orders"}, l.fields[0])
+
+	a, err = New(WithArgs([]string{}), WithEnviron([]string{}), WithLogger(l), WithExtensions(&bothInitProvider{}))
+	assert.Nil(t, err)
+	assert.EqualError(t, a.Start(), "extension both has both the Init and the InitLogger function")
+}
+
+type bothInitProvider struct{}
+
+func (p *bothInitProvider) NewExtesion() Extension {
+	return Extension{
+		Name: "both",
+		Init: func(resources embed.FS, config interface{}) error {
+			return nil
+		},
+		InitLogger: func(resources embed.FS, config interface{}, logger log.Logger) error {
+			return nil
+		},
+	}
+}
@@ -0,0 +1,184 @@
+package gluon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-gluon/gluon/config"
+)
+
+// ErrNoCommand the application has commands but no command was selected by the arguments
+var ErrNoCommand = errors.New("No command")
+
+// CommandRun run the command with the bound command configuration and the remaining positional arguments
+type CommandRun = func(config interface{}, args []string) error
+
+// Command subcommand of the application, for example `serve` or `migrate up`
+type Command struct {
+	// Name of the command used on the command line
+	Name string
+	// Usage short description of the command
+	Usage string
+	// Config pointer to the configuration struct of the command. The properties are bound
+	// without the prefix only from the command line flags, so the command flags are `--<key>`
+	// and the environment variables or the yaml properties with the same keys are ignored
+	Config interface{}
+	// Run the command, the command without the function requires a subcommand
+	Run CommandRun
+	// Commands subcommands of the command
+	Commands []Command
+}
+
+// commandPath selected command with the names of the parent commands
+type commandPath struct {
+	names   []string
+	command Command
+	args    []string
+}
+
+func (p *commandPath) String() string {
+	return strings.Join(p.names, " ")
+}
+
+// Run start the application, run the command selected by the positional arguments and close the application
+func (a *App) Run() error {
+	err := a.Start()
+	if err == nil {
+		err = a.Dispatch()
+	}
+	closeErr := a.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Dispatch bind the configuration and run the command selected by the positional arguments.
+// The application without commands does nothing.
+func (a *App) Dispatch() error {
+	if len(a.commands) == 0 {
+		return nil
+	}
+	p := a.command
+	if p == nil || p.command.Run == nil {
+		a.commandsUsage(a.output, p)
+		return ErrNoCommand
+	}
+	if p.command.Config != nil {
+		err := a.config.PropertiesFrom(p.command.Config, a.flags())
+		if err != nil {
+			return err
+		}
+	}
+	err := p.command.Run(p.command.Config, p.args)
+	if err != nil {
+		return fmt.Errorf("command %s: %w", p, err)
+	}
+	return nil
+}
+
+// addCommands add the commands of the extension, the command names are unique
+func (a *App) addCommands(e Extension) error {
+	for _, cmd := range e.Commands {
+		for _, item := range a.commands {
+			if item.Name == cmd.Name {
+				return fmt.Errorf("duplicate command %s of the extension %s", cmd.Name, e.Name)
+			}
+		}
+		a.commands = append(a.commands, cmd)
+	}
+	return nil
+}
+
+// selectCommand find the command by the positional arguments before the `--` terminator
+// and register the flags of the command configuration
+func (a *App) selectCommand() error {
+	a.command = nil
+	if len(a.commands) == 0 {
+		return nil
+	}
+	f := a.flags()
+	if f == nil {
+		return nil
+	}
+	words := f.Positional()
+	words = words[:len(words)-len(f.Rest())]
+
+	var p *commandPath
+	commands := a.commands
+	for _, word := range words {
+		found := false
+		for _, cmd := range commands {
+			if cmd.Name == word {
+				if p == nil {
+					p = &commandPath{}
+				}
+				p.names = append(p.names, cmd.Name)
+				p.command = cmd
+				commands = cmd.Commands
+				found = true
+				break
+			}
+		}
+		if !found {
+			if p == nil {
+				if f.Help() {
+					// the usage of the application is printed for the help flag
+					return nil
+				}
+				return fmt.Errorf("unknown command: %s", word)
+			}
+			break
+		}
+	}
+	if p == nil {
+		return nil
+	}
+	a.command = p
+
+	if p.command.Config != nil {
+		items, err := a.config.Describe(p.command.Config)
+		if err != nil {
+			return err
+		}
+		// the command configuration is not bound from the environment variables
+		for i := range items {
+			items[i].Env = ""
+		}
+		a.config.RegisterProperties(items...)
+	}
+	// the boolean flags of the command do not consume the following arguments
+	p.args = f.Positional()[len(p.names):]
+	return nil
+}
+
+// flags the first command line flags configuration source
+func (a *App) flags() *config.FlagsConfigSource {
+	for _, s := range a.config.Sources() {
+		if f, ok := s.(*config.FlagsConfigSource); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// commandsUsage writes the available commands of the selected command or the application
+func (a *App) commandsUsage(w io.Writer, p *commandPath) {
+	commands := a.commands
+	if p != nil {
+		commands = p.command.Commands
+	}
+	if len(commands) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		line := "  " + cmd.Name
+		if len(cmd.Usage) > 0 {
+			line += "\n    \t" + strings.ReplaceAll(cmd.Usage, "\n", "\n    \t")
+		}
+		fmt.Fprintln(w, line)
+	}
+}
@@ -3,9 +3,14 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
 	"sort"
 	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-gluon/gluon/log"
 )
 
 // ConfigSource configuration source interface
@@ -26,42 +31,88 @@ const (
 	configPrefix = "gluon."
 )
 
+var durationType = reflect.TypeOf(time.Duration(0))
+
 var (
-	// Default configuration source provider instance
-	Default *ConfigSourceProvider
+	// Default configuration source provider instance. The command line arguments and the environment
+	// variables configuration sources are created on the first use of the provider.
+	Default = &ConfigSourceProvider{load: func() (*ConfigSourceProvider, error) {
+		return NewProvider(os.Args[1:], os.Environ())
+	}}
 	// ConfigProfileProperty configuration profile property
 	ConfigProfileProperty = "config.profile"
+	// OrdinalProperty ordinal override property read from the configuration source itself
+	OrdinalProperty = "config_ordinal"
 )
 
-// init initialize default configuration
-func init() {
-	// create default configuration source provider
-	Default = &ConfigSourceProvider{}
+// NewProvider create configuration source provider with the command line arguments
+// and environment variables configuration sources and the configured profile
+func NewProvider(args, environ []string) (*ConfigSourceProvider, error) {
+	c := &ConfigSourceProvider{}
 
 	// environment configuration
-	e := &EnvConfigSource{}
+	e := &EnvConfigSource{Environ: environ}
 
 	// flags parameter configurations
-	f := &FlagsConfigSource{}
+	f := &FlagsConfigSource{Args: args}
 
 	// add default configuration sources
-	err := Default.Add(f, e)
+	err := c.Add(f, e)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	// set profile
-	profile := Default.Property(ConfigProfileProperty, "")
+	profile := c.Property(ConfigProfileProperty, "")
 	if len(profile) > 0 {
-		Default.SetProfile(profile)
+		c.SetProfile(profile)
 	}
+	return c, nil
+}
+
+// sourceEntry configuration source with the resolved ordinal
+type sourceEntry struct {
+	source    ConfigSource
+	ordinal   int
+	mutex     sync.Mutex
+	lastKnown map[string]string
+	index     map[string]string
+	version   uint64
 }
 
 // ConfigSourceProvider configuration source provider
 type ConfigSourceProvider struct {
-	sources    []ConfigSource
-	profile    string
-	profileOrg string
+	sources       []*sourceEntry
+	profile       string
+	profileOrg    string
+	exactKeys     bool
+	naming        NamingStrategy
+	types         map[reflect.Type]map[string]func() interface{}
+	changes       changes
+	errorPolicy   ErrorPolicy
+	errorPolicies map[string]ErrorPolicy
+	log           log.Logger
+	load          func() (*ConfigSourceProvider, error)
+	loadOnce      sync.Once
+}
+
+// entries configuration sources of the provider. The configuration sources and the profile
+// of the load function are added before the first use of the sources.
+func (c *ConfigSourceProvider) entries() []*sourceEntry {
+	c.loadOnce.Do(func() {
+		if c.load == nil {
+			return
+		}
+		p, err := c.load()
+		if err != nil {
+			panic(err)
+		}
+		c.sources = append(p.sources, c.sources...)
+		if len(c.profileOrg) == 0 {
+			c.SetProfile(p.profileOrg)
+		}
+	})
+	return c.sources
 }
 
 // SetProfile set profile to default provider
@@ -71,7 +122,7 @@ func SetProfile(profile string) {
 
 // Profile of the default configuration source provider
 func Profile() string {
-	return Default.profile
+	return Default.Profile()
 }
 
 // SetProfile set configuration profile to provider
@@ -86,6 +137,7 @@ func (c *ConfigSourceProvider) SetProfile(profile string) {
 
 // Profile configuration profile of the configuration source provider
 func (c *ConfigSourceProvider) Profile() string {
+	c.entries()
 	return c.profile
 }
 
@@ -104,8 +156,31 @@ func (c *ConfigSourceProvider) Properties(value interface{}) error {
 	if reflect.ValueOf(value).Kind() != reflect.Ptr {
 		return errors.New("Configuration properties is not pointer to struct")
 	}
-	c.properties("", value)
-	return nil
+	return c.bindValue("", value)
+}
+
+// PropertiesFrom setup the properties in the structure base on the tags only from the configuration
+// sources of the provider, for example only from the command line flags
+func (c *ConfigSourceProvider) PropertiesFrom(value interface{}, sources ...ConfigSource) error {
+	tmp := &ConfigSourceProvider{
+		profile:       c.profile,
+		profileOrg:    c.profileOrg,
+		exactKeys:     c.exactKeys,
+		naming:        c.naming,
+		types:         c.types,
+		errorPolicy:   c.errorPolicy,
+		errorPolicies: c.errorPolicies,
+		log:           c.log,
+	}
+	for _, entry := range c.entries() {
+		for _, item := range sources {
+			if entry.source == item {
+				tmp.sources = append(tmp.sources, entry)
+				break
+			}
+		}
+	}
+	return tmp.Properties(value)
 }
 
 // Extension setup the properties in the structure base on the tags
@@ -113,37 +188,43 @@ func (c *ConfigSourceProvider) Extension(name string, value interface{}) error {
 	if reflect.ValueOf(value).Kind() != reflect.Ptr {
 		return errors.New("Extension configuration is not pointer to struct")
 	}
-	c.properties(configPrefix+name, value)
-	return nil
+	return c.bindValue(configPrefix+name, value)
+}
+
+// bindValue add properties to the struct and call the lifecycle hooks
+func (c *ConfigSourceProvider) bindValue(prefix string, value interface{}) error {
+	c.registerValue(prefix, value)
+	err := c.properties(prefix, value)
+	if err != nil {
+		return err
+	}
+	return c.postBind(prefix, value)
 }
 
 // add properties to the struct
-func (c *ConfigSourceProvider) properties(prefix string, value interface{}) {
+func (c *ConfigSourceProvider) properties(prefix string, value interface{}) error {
 	original := reflect.ValueOf(value)
 	kind := original.Kind()
 	if kind == reflect.Ptr || kind == reflect.Interface {
 		original = reflect.Indirect(original)
 	} else {
-		return
-	}
-
-	typeof := reflect.TypeOf(value)
-	if typeof.Kind() == reflect.Ptr {
-		typeof = typeof.Elem()
+		return nil
 	}
+	return c.bind(prefix, original)
+}
 
-	// fmt.Printf("NumField: %v\n", typeof.NumField())
-	for i := 0; i < typeof.NumField(); i++ {
-		f := typeof.Field(i)
-		prop := f.Name
-		tag, ok := f.Tag.Lookup("config")
-		if ok {
-			prop = tag
+// bind add properties to the struct value
+func (c *ConfigSourceProvider) bind(prefix string, original reflect.Value) error {
+	var result error
+	c.fields(prefix, original, func(f reflect.StructField, field reflect.Value, prop string) {
+		if result != nil {
+			return
 		}
-		if prefix != "" {
-			prop = prefix + "." + tag
+		if field.Type() == durationType {
+			tmp := c.PropertyDuration(prop, time.Duration(field.Int()))
+			field.SetInt(int64(tmp))
+			return
 		}
-		field := original.Field(i)
 		switch field.Kind() {
 		case reflect.String:
 			tmp := c.Property(prop, field.String())
@@ -160,17 +241,66 @@ func (c *ConfigSourceProvider) properties(prefix string, value interface{}) {
 			tmp := c.Property(prop, toString(field.Bool()))
 			b, _ := strconv.ParseBool(tmp)
 			field.SetBool(b)
+		case reflect.Slice:
+			c.bindSlice(prop, field)
 		case reflect.Struct:
-
-			fval := reflect.New(f.Type)
-			c.properties(prop, fval.Interface())
-			field.Set(fval.Elem())
+			result = c.bind(prop, field)
+		case reflect.Interface:
+			result = c.bindInterface(prop, f, field)
 		default:
-			fmt.Printf("Not supported field: %v type: %v\n", f.Name, field.Kind())
+			c.logger().Warn("Not supported configuration field", log.Fields{"field": f.Name, "type": field.Type().String()})
 		}
+	})
+	return result
+}
 
+// bindSlice set the list property to the slice field
+func (c *ConfigSourceProvider) bindSlice(prop string, field reflect.Value) {
+	items := c.PropertyStrings(prop, nil)
+	if items == nil {
+		return
+	}
+	tmp := reflect.MakeSlice(field.Type(), len(items), len(items))
+	for i, item := range items {
+		supported, err := setValue(tmp.Index(i), item)
+		if !supported {
+			c.logger().Warn("Not supported configuration field", log.Fields{"property": prop, "type": field.Type().String()})
+			return
+		}
+		if err != nil {
+			c.logError(prop, invalidValue(prop, err))
+			return
+		}
 	}
+	field.Set(tmp)
+}
 
+// setValue parse and set the simple value
+func setValue(field reflect.Value, value string) (bool, error) {
+	if field.Type() == durationType {
+		tmp, err := time.ParseDuration(value)
+		field.SetInt(int64(tmp))
+		return true, err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Float32, reflect.Float64:
+		tmp, err := strconv.ParseFloat(value, 64)
+		field.SetFloat(tmp)
+		return true, err
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64:
+		tmp, err := strconv.ParseInt(value, 10, 0)
+		field.SetInt(tmp)
+		return true, err
+	case reflect.Bool:
+		tmp, err := strconv.ParseBool(value)
+		field.SetBool(tmp)
+		return true, err
+	default:
+		return false, nil
+	}
+	return true, nil
 }
 
 // PropertyBool bool value property from the default configuration source provider
@@ -178,18 +308,35 @@ func PropertyBool(name string, defaultValue bool) bool {
 	return Default.PropertyBool(name, defaultValue)
 }
 
+// PropertyBoolE bool value property from the default configuration source provider with the error
+func PropertyBoolE(name string, defaultValue bool) (bool, error) {
+	return Default.PropertyBoolE(name, defaultValue)
+}
+
 // PropertyBool bool value property from the configuration source provider
 func (c *ConfigSourceProvider) PropertyBool(name string, defaultValue bool) bool {
-	value, exists := c.findProperty(name)
+	value, err := c.PropertyBoolE(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyBoolE bool value property from the configuration source provider with the error
+func (c *ConfigSourceProvider) PropertyBoolE(name string, defaultValue bool) (bool, error) {
+	value, exists, err := c.findProperty(name)
+	if err != nil {
+		return defaultValue, err
+	}
 	if exists {
 		tmp, err := strconv.ParseBool(value)
 		if err != nil {
-			//TODO: debug log
-			return defaultValue
+			return defaultValue, invalidValue(name, err)
 		}
-		return tmp
+		return tmp, nil
 	}
-	return defaultValue
+	return defaultValue, nil
 }
 
 // PropertyInt int value property from the default configuration source provider
@@ -197,18 +344,35 @@ func PropertyInt(name string, defaultValue int) int {
 	return Default.PropertyInt(name, defaultValue)
 }
 
+// PropertyIntE int value property from the default configuration source provider with the error
+func PropertyIntE(name string, defaultValue int) (int, error) {
+	return Default.PropertyIntE(name, defaultValue)
+}
+
 // PropertyInt int value property from the configuration source provider
 func (c *ConfigSourceProvider) PropertyInt(name string, defaultValue int) int {
-	value, exists := c.findProperty(name)
+	value, err := c.PropertyIntE(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyIntE int value property from the configuration source provider with the error
+func (c *ConfigSourceProvider) PropertyIntE(name string, defaultValue int) (int, error) {
+	value, exists, err := c.findProperty(name)
+	if err != nil {
+		return defaultValue, err
+	}
 	if exists {
 		tmp, err := strconv.Atoi(value)
 		if err != nil {
-			//TODO: debug log
-			return defaultValue
+			return defaultValue, invalidValue(name, err)
 		}
-		return tmp
+		return tmp, nil
 	}
-	return defaultValue
+	return defaultValue, nil
 }
 
 // Property string value property from the default configuration source provider
@@ -216,13 +380,31 @@ func Property(name string, defaultValue string) string {
 	return Default.Property(name, defaultValue)
 }
 
+// PropertyE string value property from the default configuration source provider with the error
+func PropertyE(name string, defaultValue string) (string, error) {
+	return Default.PropertyE(name, defaultValue)
+}
+
 // Property string value property from the configuration source provider
 func (c *ConfigSourceProvider) Property(name, defaultValue string) string {
-	value, exists := c.findProperty(name)
+	value, err := c.PropertyE(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyE string value property from the configuration source provider with the error
+func (c *ConfigSourceProvider) PropertyE(name, defaultValue string) (string, error) {
+	value, exists, err := c.findProperty(name)
+	if err != nil {
+		return defaultValue, err
+	}
 	if exists {
-		return value
+		return value, nil
 	}
-	return defaultValue
+	return defaultValue, nil
 }
 
 // Add methods add configuration sources to the default provider
@@ -232,45 +414,112 @@ func Add(s ...ConfigSource) error {
 
 // Add methods add configuration sources
 func (c *ConfigSourceProvider) Add(s ...ConfigSource) error {
+	c.entries()
 	if len(s) > 0 {
 		for _, item := range s {
 			err := item.Init()
 			if err != nil {
 				return err
 			}
-			c.sources = append(c.sources, item)
+			entry := &sourceEntry{source: item, ordinal: item.Priority()}
+			c.reindex(entry)
+			c.sources = append(c.sources, entry)
 		}
 	}
+	c.sort()
+	return nil
+}
+
+// Remove methods remove configuration sources from the provider
+func (c *ConfigSourceProvider) Remove(s ...ConfigSource) {
+	for _, item := range s {
+		for i, entry := range c.entries() {
+			if entry.source == item {
+				c.sources = append(c.sources[:i], c.sources[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Sources configuration sources ordered by the ordinal, the first source wins
+func (c *ConfigSourceProvider) Sources() []ConfigSource {
+	entries := c.entries()
+	result := make([]ConfigSource, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.source
+	}
+	return result
+}
+
+// Ordinal resolved ordinal of the configuration source. The ordinal is the first found value of
+// `gluon.config.sources.<name>.ordinal`, the `config_ordinal` property of the source itself
+// and the source priority.
+func (c *ConfigSourceProvider) Ordinal(s ConfigSource) int {
+	for _, entry := range c.entries() {
+		if entry.source == s {
+			return entry.ordinal
+		}
+	}
+	return s.Priority()
+}
+
+// sort resolve the ordinal of the configuration sources and sort them
+func (c *ConfigSourceProvider) sort() {
+	for _, entry := range c.sources {
+		entry.ordinal = c.ordinal(entry.source)
+	}
 	sort.SliceStable(c.sources, func(i, j int) bool {
-		return c.sources[i].Priority() > c.sources[j].Priority()
+		return c.sources[i].ordinal > c.sources[j].ordinal
 	})
-	return nil
 }
 
-func (c *ConfigSourceProvider) findProperty(name string) (string, bool) {
-	if len(c.sources) > 0 {
-		for _, source := range c.sources {
+func (c *ConfigSourceProvider) ordinal(s ConfigSource) int {
+	value, exists, err := c.findProperty(configPrefix + "config.sources." + s.Name() + ".ordinal")
+	if err == nil && exists {
+		tmp, err := strconv.Atoi(value)
+		if err == nil {
+			return tmp
+		}
+	}
+	value, exists, err = s.Property(OrdinalProperty)
+	if err == nil && exists {
+		tmp, err := strconv.Atoi(value)
+		if err == nil {
+			return tmp
+		}
+	}
+	return s.Priority()
+}
+
+func (c *ConfigSourceProvider) findProperty(name string) (string, bool, error) {
+	value, _, exists, err := c.lookup(name)
+	return value, exists, err
+}
+
+// lookup find the property and the configuration source of the value
+func (c *ConfigSourceProvider) lookup(name string) (string, ConfigSource, bool, error) {
+	if entries := c.entries(); len(entries) > 0 {
+		for _, entry := range entries {
 			if len(c.profile) > 0 {
-				value, exists, err := source.Property(c.profile + name)
+				value, exists, err := c.sourceProperty(entry, c.profile+name)
 				if err != nil {
-					//TODO: debug log
-					return "", false
+					return "", nil, false, err
 				}
 				if exists {
-					return value, true
+					return value, entry.source, true, nil
 				}
 			}
-			value, exists, err := source.Property(name)
+			value, exists, err := c.sourceProperty(entry, name)
 			if err != nil {
-				//TODO: debug log
-				return "", false
+				return "", nil, false, err
 			}
 			if exists {
-				return value, true
+				return value, entry.source, true, nil
 			}
 		}
 	}
-	return "", false
+	return "", nil, false, nil
 }
 
 func toString(data interface{}) string {
@@ -71,3 +71,48 @@ func TestInput(t *testing.T) {
 	assert.Nil(t, err)
 	t.Logf("Input2 %v\n", input2)
 }
+
+func TestOrdinal(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	e := &EnvConfigSource{Environ: []string{"APP_NAME=env"}}
+	f := &FlagsConfigSource{Args: []string{"--app-name=flags"}}
+	err := csp.Add(f, e)
+	assert.Nil(t, err)
+	assert.Equal(t, "env", csp.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, []ConfigSource{e, f}, csp.Sources())
+
+	// ordinal from the source itself
+	csp = &ConfigSourceProvider{}
+	e = &EnvConfigSource{Environ: []string{"APP_NAME=env"}}
+	f = &FlagsConfigSource{Args: []string{"--app-name=flags", "--config_ordinal=400"}}
+	err = csp.Add(f, e)
+	assert.Nil(t, err)
+	assert.Equal(t, "flags", csp.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, 400, csp.Ordinal(f))
+	assert.Equal(t, 300, csp.Ordinal(e))
+
+	// ordinal from the gluon configuration
+	csp = &ConfigSourceProvider{}
+	e = &EnvConfigSource{Environ: []string{"APP_NAME=env", "GLUON_CONFIG_SOURCES_ENV_ORDINAL=50"}}
+	f = &FlagsConfigSource{Args: []string{"--app-name=flags"}}
+	err = csp.Add(e)
+	assert.Nil(t, err)
+	err = csp.Add(f)
+	assert.Nil(t, err)
+	assert.Equal(t, "flags", csp.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, 50, csp.Ordinal(e))
+}
+
+func TestLazyProvider(t *testing.T) {
+	loaded := 0
+	c := &ConfigSourceProvider{load: func() (*ConfigSourceProvider, error) {
+		loaded++
+		return NewProvider([]string{"--app-name=flag", "--config-profile=dev"}, []string{})
+	}}
+	assert.Equal(t, 0, loaded)
+
+	assert.Equal(t, "flag", c.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, "+dev.", c.Profile())
+	assert.Equal(t, 2, len(c.Sources()))
+	assert.Equal(t, 1, loaded)
+}
@@ -0,0 +1,180 @@
+// Package configtest provides helpers to test the configuration without touching
+// the process environment, the command line arguments or the default provider.
+package configtest
+
+import (
+	"embed"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/go-gluon/gluon"
+	"github.com/go-gluon/gluon/config"
+)
+
+// OverridePriority priority of the override configuration source
+const OverridePriority = math.MaxInt32
+
+var _ config.MutableConfigSource = &MapConfigSource{}
+
+// MapConfigSource in-memory configuration source
+type MapConfigSource struct {
+	name     string
+	priority int
+	mutex    sync.RWMutex
+	data     map[string]string
+	version  uint64
+}
+
+// NewMapConfigSource create in-memory configuration source with the properties
+func NewMapConfigSource(name string, priority int, properties map[string]string) *MapConfigSource {
+	data := make(map[string]string, len(properties))
+	for k, v := range properties {
+		data[k] = v
+	}
+	return &MapConfigSource{name: name, priority: priority, data: data}
+}
+
+func (m *MapConfigSource) Init() error {
+	return nil
+}
+
+func (m *MapConfigSource) Priority() int {
+	return m.priority
+}
+
+func (m *MapConfigSource) Name() string {
+	return m.name
+}
+
+func (m *MapConfigSource) Property(name string) (string, bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	v, e := m.data[name]
+	return v, e, nil
+}
+
+func (m *MapConfigSource) Properties() (map[string]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	tmp := make(map[string]string, len(m.data))
+	for k, v := range m.data {
+		tmp[k] = v
+	}
+	return tmp, nil
+}
+
+// Set set the property value
+func (m *MapConfigSource) Set(name, value string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.data[name] = value
+	m.version++
+	return nil
+}
+
+// Delete delete the property
+func (m *MapConfigSource) Delete(name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.data, name)
+	m.version++
+	return nil
+}
+
+// Version of the properties, changed after every change
+func (m *MapConfigSource) Version() uint64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.version
+}
+
+// NewProvider create isolated configuration source provider with the sources.
+// The provider does not read the process environment or command line arguments.
+func NewProvider(t testing.TB, sources ...config.ConfigSource) *config.ConfigSourceProvider {
+	t.Helper()
+	c := &config.ConfigSourceProvider{}
+	err := c.Add(sources...)
+	if err != nil {
+		t.Fatalf("configtest: add configuration sources: %v", err)
+	}
+	return c
+}
+
+// NewMapProvider create isolated configuration source provider with in-memory properties
+func NewMapProvider(t testing.TB, properties map[string]string) *config.ConfigSourceProvider {
+	t.Helper()
+	return NewProvider(t, NewMapConfigSource("map", 0, properties))
+}
+
+// override source of the overridden properties shared by the tests of the provider
+type override struct {
+	source *MapConfigSource
+	count  int
+}
+
+var (
+	overridesMutex sync.Mutex
+	overrides      = map[*config.ConfigSourceProvider]*override{}
+)
+
+// Override override the property in the default provider until the end of the test
+func Override(t testing.TB, name, value string) {
+	t.Helper()
+	OverrideIn(t, config.Default, name, value)
+}
+
+// OverrideIn override the property in the provider until the end of the test, the override
+// source is removed from the provider after the last override is restored
+func OverrideIn(t testing.TB, c *config.ConfigSourceProvider, name, value string) {
+	t.Helper()
+
+	overridesMutex.Lock()
+	o, exists := overrides[c]
+	if !exists {
+		o = &override{source: NewMapConfigSource("override", OverridePriority, nil)}
+		overrides[c] = o
+	}
+	o.count++
+	overridesMutex.Unlock()
+
+	if !exists {
+		err := c.Add(o.source)
+		if err != nil {
+			release(c, o)
+			t.Fatalf("configtest: add override configuration source: %v", err)
+		}
+	}
+
+	previous, found, _ := o.source.Property(name)
+	_ = o.source.Set(name, value)
+	t.Cleanup(func() {
+		if found {
+			_ = o.source.Set(name, previous)
+		} else {
+			_ = o.source.Delete(name)
+		}
+		release(c, o)
+	})
+}
+
+// release the override, the last release removes the override source from the provider
+func release(c *config.ConfigSourceProvider, o *override) {
+	overridesMutex.Lock()
+	defer overridesMutex.Unlock()
+	o.count--
+	if o.count > 0 {
+		return
+	}
+	delete(overrides, c)
+	c.Remove(o.source)
+}
+
+// RegisterExtensions register extensions with the isolated configuration source provider
+func RegisterExtensions(t testing.TB, c *config.ConfigSourceProvider, resources embed.FS, providers ...gluon.ExtensionProvider) {
+	t.Helper()
+	err := gluon.RegisterExtensionsWith(c, resources, providers...)
+	if err != nil {
+		t.Fatalf("configtest: register extensions: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package configtest
+
+import (
+	"testing"
+
+	"github.com/go-gluon/gluon/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverride(t *testing.T) {
+	c := NewMapProvider(t, map[string]string{"app.name": "map"})
+	assert.Equal(t, "map", c.Property("app.name", "NO_VALUE"))
+
+	t.Run("override", func(t *testing.T) {
+		OverrideIn(t, c, "app.name", "override")
+		OverrideIn(t, c, "app.port", "8080")
+		assert.Equal(t, "override", c.Property("app.name", "NO_VALUE"))
+		assert.Equal(t, 8080, c.PropertyInt("app.port", 0))
+	})
+
+	assert.Equal(t, "map", c.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, 0, c.PropertyInt("app.port", 0))
+	assert.Equal(t, 1, len(c.Sources()))
+	assert.NotContains(t, overrides, c)
+}
+
+func TestOverrideRelaxedKeys(t *testing.T) {
+	source := NewMapConfigSource("map", 0, nil)
+	c := NewProvider(t, source)
+
+	t.Run("override", func(t *testing.T) {
+		OverrideIn(t, c, "app.max-pool-size", "5")
+		assert.Equal(t, 5, c.PropertyInt("app.maxPoolSize", 0))
+	})
+	assert.Equal(t, 0, c.PropertyInt("app.maxPoolSize", 0))
+
+	source.Set("APP_MAX_POOL_SIZE", "10")
+	assert.Equal(t, 10, c.PropertyInt("app.maxPoolSize", 0))
+}
+
+func TestOverrideDefault(t *testing.T) {
+	t.Run("override", func(t *testing.T) {
+		Override(t, "configtest.value", "1")
+		assert.Equal(t, "1", config.Property("configtest.value", "NO_VALUE"))
+	})
+	assert.Equal(t, "NO_VALUE", config.Property("configtest.value", "NO_VALUE"))
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PropertyDescription description of the configuration property
+type PropertyDescription struct {
+	// Key full name of the property
+	Key string
+	// Env environment variable name of the property
+	Env string
+	// Flag command line flag name of the property
+	Flag string
+	// Short alias of the command line flag from the `short` tag
+	Short string
+	// Type of the property
+	Type string
+	// Default value of the property
+	Default string
+	// Description of the property from the `description` tag
+	Description string
+}
+
+// Describe describes the properties of the structure base on the tags
+func Describe(value interface{}) ([]PropertyDescription, error) {
+	return Default.Describe(value)
+}
+
+// DescribeExtension describes the properties of the extension structure base on the tags
+func DescribeExtension(name string, value interface{}) ([]PropertyDescription, error) {
+	return Default.DescribeExtension(name, value)
+}
+
+// Describe describes the properties of the structure base on the tags
+func (c *ConfigSourceProvider) Describe(value interface{}) ([]PropertyDescription, error) {
+	if reflect.ValueOf(value).Kind() != reflect.Ptr {
+		return nil, errors.New("Configuration properties is not pointer to struct")
+	}
+	result := []PropertyDescription{}
+	c.describe("", reflect.Indirect(reflect.ValueOf(value)), &result)
+	return result, nil
+}
+
+// DescribeExtension describes the properties of the extension structure base on the tags
+func (c *ConfigSourceProvider) DescribeExtension(name string, value interface{}) ([]PropertyDescription, error) {
+	if reflect.ValueOf(value).Kind() != reflect.Ptr {
+		return nil, errors.New("Extension configuration is not pointer to struct")
+	}
+	result := []PropertyDescription{}
+	c.describe(configPrefix+name, reflect.Indirect(reflect.ValueOf(value)), &result)
+	return result, nil
+}
+
+// PropertyRegistry is implemented by the configuration sources which need to know
+// the properties of the bound configuration structures
+type PropertyRegistry interface {
+	// RegisterProperties register the property descriptions
+	RegisterProperties(items ...PropertyDescription)
+}
+
+// RegisterProperties register the property descriptions to all configuration sources
+// which implement the PropertyRegistry interface
+func (c *ConfigSourceProvider) RegisterProperties(items ...PropertyDescription) {
+	for _, entry := range c.entries() {
+		if r, ok := entry.source.(PropertyRegistry); ok {
+			r.RegisterProperties(items...)
+			c.reindex(entry)
+		}
+	}
+}
+
+// registerValue register the property descriptions of the bound structure
+func (c *ConfigSourceProvider) registerValue(prefix string, value interface{}) {
+	result := []PropertyDescription{}
+	c.describe(prefix, reflect.Indirect(reflect.ValueOf(value)), &result)
+	c.RegisterProperties(result...)
+}
+
+// describe add the property descriptions of the struct
+func (c *ConfigSourceProvider) describe(prefix string, original reflect.Value, result *[]PropertyDescription) {
+	c.fields(prefix, original, func(f reflect.StructField, field reflect.Value, prop string) {
+		switch field.Kind() {
+		case reflect.String, reflect.Float32, reflect.Float64, reflect.Bool,
+			reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Slice:
+			*result = append(*result, PropertyDescription{
+				Key:         prop,
+				Env:         c.envName(prop),
+				Flag:        "--" + FlagName(prop),
+				Short:       f.Tag.Get("short"),
+				Type:        f.Type.String(),
+				Default:     valueString(field),
+				Description: f.Tag.Get("description"),
+			})
+		case reflect.Struct:
+			c.describe(prop, field, result)
+		case reflect.Interface:
+			c.describeInterface(prop, f, field, result)
+		}
+	})
+}
+
+// envName environment variable name of the property with the prefix of the first environment
+// variables configuration source
+func (c *ConfigSourceProvider) envName(name string) string {
+	for _, entry := range c.entries() {
+		if e, ok := entry.source.(*EnvConfigSource); ok {
+			return e.Prefix + EnvName(name)
+		}
+	}
+	return EnvName(name)
+}
+
+// valueString string representation of the simple field value
+func valueString(field reflect.Value) string {
+	if field.Type() == durationType {
+		return time.Duration(field.Int()).String()
+	}
+	switch field.Kind() {
+	case reflect.Slice:
+		tmp := make([]string, field.Len())
+		for i := range tmp {
+			tmp[i] = valueString(field.Index(i))
+		}
+		return strings.Join(tmp, ",")
+	case reflect.String:
+		return field.String()
+	case reflect.Float32, reflect.Float64:
+		return toString(field.Float())
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64:
+		return toString(field.Int())
+	case reflect.Bool:
+		return toString(field.Bool())
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DescribeStruct struct {
+	Name string `config:"name" description:"Name of the service"`
+	Port int    `config:"port" description:"Listen port"`
+	Db   struct {
+		User string `config:"user"`
+	} `config:"db"`
+}
+
+func TestDescribeExtension(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	items, err := csp.DescribeExtension("test", &DescribeStruct{Port: 8080})
+	assert.Nil(t, err)
+	assert.Equal(t, []PropertyDescription{
+		{Key: "gluon.test.name", Env: "GLUON_TEST_NAME", Flag: "--gluon-test-name", Type: "string", Description: "Name of the service"},
+		{Key: "gluon.test.port", Env: "GLUON_TEST_PORT", Flag: "--gluon-test-port", Type: "int", Default: "8080", Description: "Listen port"},
+		{Key: "gluon.test.db.user", Env: "GLUON_TEST_DB_USER", Flag: "--gluon-test-db-user", Type: "string"},
+	}, items)
+
+	_, err = csp.Describe(DescribeStruct{})
+	assert.NotNil(t, err)
+}
+
+func TestDescribeEnvPrefix(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: []string{}, Prefix: "MYAPP_"})
+	assert.Nil(t, err)
+	items, err := csp.DescribeExtension("test", &DescribeStruct{})
+	assert.Nil(t, err)
+	assert.Equal(t, "MYAPP_GLUON_TEST_NAME", items[0].Env)
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"os"
+	"path"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 var envRegexp = createRegexp()
@@ -20,7 +22,21 @@ func createRegexp() *regexp.Regexp {
 }
 
 type EnvConfigSource struct {
-	envs map[string]string
+	// Environ environment variables in the form "key=value", default os.Environ()
+	Environ []string
+	// Prefix required prefix of the environment variables, for example `MYAPP_`.
+	// Variables without the prefix are ignored and the prefix is stripped before matching.
+	Prefix string
+	// Allow glob patterns of the allowed environment variables (without the prefix), empty allows all
+	Allow []string
+	// Deny glob patterns of the denied environment variables (without the prefix)
+	Deny []string
+	// IgnoreCase match the environment variables regardless of the case,
+	// by default only the upper-case variables match the properties
+	IgnoreCase bool
+	envs       map[string]string
+	mutex      sync.RWMutex
+	known      map[string]string
 }
 
 func (f *EnvConfigSource) Init() error {
@@ -37,23 +53,144 @@ func (f *EnvConfigSource) Name() string {
 }
 
 func (f *EnvConfigSource) Property(name string) (string, bool, error) {
-	tmp := envRegexp.ReplaceAllString(name, "_")
-	tmp = strings.ToUpper(tmp)
-	v, e := f.envs[tmp]
+	v, e := f.envs[EnvName(name)]
 	return v, e, nil
 }
 
+// EnvName environment variable name of the property
+func EnvName(name string) string {
+	tmp := envRegexp.ReplaceAllString(name, "_")
+	return strings.ToUpper(tmp)
+}
+
+// EnvPropertyName canonical dotted property name of the environment variable,
+// `APP_DB_USER` to `app.db.user`, `SERVERS_0_HOST` to `servers[0].host`
+// and `_DEV_APP_DB_USER` to `+dev.app.db.user`
+func EnvPropertyName(env string) string {
+	prefix := ""
+	if strings.HasPrefix(env, "_") {
+		prefix = "+"
+	}
+	items := strings.FieldsFunc(strings.ToLower(env), func(r rune) bool {
+		return r == '_'
+	})
+	b := strings.Builder{}
+	b.WriteString(prefix)
+	for i, item := range items {
+		if i > 0 && isNumber(item) {
+			b.WriteString("[" + item + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(item)
+	}
+	return b.String()
+}
+
+// isNumber returns true if the value contains only digits
+func isNumber(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return len(value) > 0
+}
+
+// RegisterProperties register the known properties, the environment variables of the known
+// properties have the property name, `APP_MAX_POOL_SIZE` is `app.max-pool-size`
+func (f *EnvConfigSource) RegisterProperties(items ...PropertyDescription) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.known == nil {
+		f.known = map[string]string{}
+	}
+	for _, item := range items {
+		f.known[EnvName(item.Key)] = item.Key
+	}
+}
+
+// propertyName name of the known property of the environment variable or the canonical dotted name
+func (f *EnvConfigSource) propertyName(env string) string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	if key, exists := f.known[env]; exists {
+		return key
+	}
+	// profile variable `_DEV_<NAME>` of the known property
+	if strings.HasPrefix(env, "_") {
+		if i := strings.Index(env[1:], "_"); i > 0 {
+			if key, exists := f.known[env[i+2:]]; exists {
+				return "+" + strings.ToLower(env[1:i+1]) + "." + key
+			}
+		}
+	}
+	return EnvPropertyName(env)
+}
+
+// Properties properties of the environment variables with the names of the known properties
+// or with the canonical dotted names
 func (f *EnvConfigSource) Properties() (map[string]string, error) {
-	return f.envs, nil
+	result := make(map[string]string, len(f.envs))
+	for k, v := range f.envs {
+		result[f.propertyName(k)] = v
+	}
+	return result, nil
 }
 
 func (f *EnvConfigSource) parseEnv() error {
-	items := os.Environ()
-	if len(items) > 0 {
-		for _, item := range items {
-			tmp := strings.Split(item, "=")
-			f.envs[tmp[0]] = tmp[1]
+	items := f.Environ
+	if items == nil {
+		items = os.Environ()
+	}
+	for _, item := range items {
+		tmp := strings.SplitN(item, "=", 2)
+		if len(tmp) != 2 || len(tmp[0]) == 0 {
+			continue
+		}
+		name, ok := f.filter(tmp[0])
+		if ok {
+			f.envs[name] = tmp[1]
 		}
 	}
 	return nil
 }
+
+// filter strip the prefix of the environment variable and check the allow and deny lists
+func (f *EnvConfigSource) filter(name string) (string, bool) {
+	if f.IgnoreCase {
+		name = strings.ToUpper(name)
+	}
+	if len(f.Prefix) > 0 {
+		prefix := f.Prefix
+		if f.IgnoreCase {
+			prefix = strings.ToUpper(prefix)
+		}
+		if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			return "", false
+		}
+		name = name[len(prefix):]
+	}
+	if len(f.Allow) > 0 && !matchAny(f.Allow, name, f.IgnoreCase) {
+		return "", false
+	}
+	if matchAny(f.Deny, name, f.IgnoreCase) {
+		return "", false
+	}
+	return name, true
+}
+
+// matchAny returns true if the name matches any glob pattern
+func matchAny(patterns []string, name string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		if ignoreCase {
+			pattern = strings.ToUpper(pattern)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -58,3 +58,72 @@ func TestEnvConfigSource(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "5678", s2.Simple.Env)
 }
+
+func TestEnvConfigSourceOptions(t *testing.T) {
+	e := &EnvConfigSource{
+		Environ: []string{
+			"MYAPP_APP_DB_URL=postgres://host/db?sslmode=disable&a=b",
+			"MYAPP_APP_SECRET=secret",
+			"MYAPP_AWS_REGION=eu",
+			"MYAPP__DEV_APP_NAME=dev",
+			"myapp_app_lower=lower",
+			"APP_NAME=other",
+			"MYAPP_",
+			"INVALID",
+		},
+		Prefix: "MYAPP_",
+		Allow:  []string{"APP_*", "_DEV_*"},
+		Deny:   []string{"*SECRET*"},
+	}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(e)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "postgres://host/db?sslmode=disable&a=b", csp.Property("app.db.url", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("app.secret", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("aws.region", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("app.lower", "NO_VALUE"))
+
+	properties, err := e.Properties()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"app.db.url":    "postgres://host/db?sslmode=disable&a=b",
+		"+dev.app.name": "dev",
+	}, properties)
+
+	csp.SetProfile("dev")
+	assert.Equal(t, "dev", csp.Property("app.name", "NO_VALUE"))
+
+	csp = &ConfigSourceProvider{}
+	err = csp.Add(&EnvConfigSource{Environ: e.Environ, Prefix: "myapp_", IgnoreCase: true, Allow: []string{"app_*"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "lower", csp.Property("app.lower", "NO_VALUE"))
+	assert.Equal(t, "secret", csp.Property("app.secret", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("aws.region", "NO_VALUE"))
+}
+
+func TestEnvPropertyName(t *testing.T) {
+	assert.Equal(t, "app.db.user", EnvPropertyName("APP_DB_USER"))
+	assert.Equal(t, "servers[0].host", EnvPropertyName("SERVERS_0_HOST"))
+	assert.Equal(t, "matrix[1][2]", EnvPropertyName("MATRIX_1_2"))
+	assert.Equal(t, "+dev.app.name", EnvPropertyName("_DEV_APP_NAME"))
+
+	e := &EnvConfigSource{Environ: []string{"APP_MAX_POOL_SIZE=10", "_DEV_APP_MAX_POOL_SIZE=20", "APP_NAME=env"}}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(e)
+	assert.Nil(t, err)
+	csp.RegisterProperties(PropertyDescription{Key: "app.max-pool-size"})
+
+	properties, err := e.Properties()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"app.max-pool-size":      "10",
+		"+dev.app.max-pool-size": "20",
+		"app.name":               "env",
+	}, properties)
+
+	m, err := csp.PropertyMapE("app", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"max-pool-size": "10", "name": "env"}, m)
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/go-gluon/gluon/log"
+)
+
+// ErrorPolicy error handling policy of the configuration source
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyFail stops the property lookup and returns the error
+	ErrorPolicyFail ErrorPolicy = iota
+	// ErrorPolicySkip logs the error and continues with the next configuration source
+	ErrorPolicySkip
+	// ErrorPolicyLastKnown logs the error and uses the last known value of the property
+	ErrorPolicyLastKnown
+)
+
+// SourceError error of the configuration source
+type SourceError struct {
+	// Source name of the configuration source
+	Source string
+	// Property name of the property
+	Property string
+	// Err original error
+	Err error
+}
+
+func (e *SourceError) Error() string {
+	if len(e.Property) == 0 {
+		return fmt.Sprintf("configuration source %s: %v", e.Source, e.Err)
+	}
+	return fmt.Sprintf("configuration source %s property %s: %v", e.Source, e.Property, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// SetErrorPolicy set default error policy of the configuration sources to the default provider
+func SetErrorPolicy(policy ErrorPolicy) {
+	Default.SetErrorPolicy(policy)
+}
+
+// SetSourceErrorPolicy set error policy of the configuration source to the default provider
+func SetSourceErrorPolicy(name string, policy ErrorPolicy) {
+	Default.SetSourceErrorPolicy(name, policy)
+}
+
+// SetErrorPolicy set default error policy of the configuration sources
+func (c *ConfigSourceProvider) SetErrorPolicy(policy ErrorPolicy) {
+	c.errorPolicy = policy
+}
+
+// SetSourceErrorPolicy set error policy of the configuration source by the source name
+func (c *ConfigSourceProvider) SetSourceErrorPolicy(name string, policy ErrorPolicy) {
+	if c.errorPolicies == nil {
+		c.errorPolicies = map[string]ErrorPolicy{}
+	}
+	c.errorPolicies[name] = policy
+}
+
+// SetLogger set logger of the configuration source provider, default is the global logger
+func (c *ConfigSourceProvider) SetLogger(logger log.Logger) {
+	c.log = logger
+}
+
+// logger of the configuration source provider
+func (c *ConfigSourceProvider) logger() log.Logger {
+	if c.log != nil {
+		return c.log
+	}
+	return log.Log
+}
+
+func (c *ConfigSourceProvider) sourceErrorPolicy(s ConfigSource) ErrorPolicy {
+	if policy, exists := c.errorPolicies[s.Name()]; exists {
+		return policy
+	}
+	return c.errorPolicy
+}
+
+// sourceProperty get the property from the configuration source with the error policy of the source
+func (c *ConfigSourceProvider) sourceProperty(entry *sourceEntry, name string) (string, bool, error) {
+	policy := c.sourceErrorPolicy(entry.source)
+	value, exists, err := c.rawProperty(entry, name)
+	if err == nil {
+		if policy == ErrorPolicyLastKnown {
+			entry.remember(name, value, exists)
+		}
+		return value, exists, nil
+	}
+
+	err = &SourceError{Source: entry.source.Name(), Property: name, Err: err}
+	switch policy {
+	case ErrorPolicySkip:
+		c.logger().Warn("Skip configuration source", log.Err(err))
+		return "", false, nil
+	case ErrorPolicyLastKnown:
+		value, exists = entry.last(name)
+		c.logger().Warn("Use last known value of the property", log.Err(err).Add("exists", exists))
+		return value, exists, nil
+	}
+	return "", false, err
+}
+
+// sourceProperties get all properties from the configuration source with the error policy of the source
+func (c *ConfigSourceProvider) sourceProperties(entry *sourceEntry) (map[string]string, error) {
+	properties, err := entry.source.Properties()
+	if err == nil {
+		return properties, nil
+	}
+	err = &SourceError{Source: entry.source.Name(), Err: err}
+	if c.sourceErrorPolicy(entry.source) == ErrorPolicyFail {
+		return nil, err
+	}
+	c.logger().Warn("Skip configuration source", log.Err(err))
+	return map[string]string{}, nil
+}
+
+// remember last known value of the property
+func (e *sourceEntry) remember(name, value string, exists bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !exists {
+		delete(e.lastKnown, name)
+		return
+	}
+	if e.lastKnown == nil {
+		e.lastKnown = map[string]string{}
+	}
+	e.lastKnown[name] = value
+}
+
+// last known value of the property
+func (e *sourceEntry) last(name string) (string, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	value, exists := e.lastKnown[name]
+	return value, exists
+}
+
+// logError log the property error of the accessor without the error
+func (c *ConfigSourceProvider) logError(name string, err error) {
+	c.logger().Warn("Invalid configuration property, use default value", log.Err(err).Add("property", name))
+}
+
+// invalidValue error of the invalid property value
+func invalidValue(name string, err error) error {
+	return fmt.Errorf("invalid value of the property %s: %w", name, err)
+}
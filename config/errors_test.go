@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingConfigSource struct {
+	fail bool
+	data map[string]string
+}
+
+func (f *failingConfigSource) Init() error   { return nil }
+func (f *failingConfigSource) Name() string  { return "failing" }
+func (f *failingConfigSource) Priority() int { return 500 }
+func (f *failingConfigSource) Properties() (map[string]string, error) {
+	return f.data, nil
+}
+func (f *failingConfigSource) Property(name string) (string, bool, error) {
+	if f.fail {
+		return "", false, errors.New("source not available")
+	}
+	v, e := f.data[name]
+	return v, e, nil
+}
+
+func TestErrorPolicy(t *testing.T) {
+	s := &failingConfigSource{data: map[string]string{"app.port": "80"}}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(s, &EnvConfigSource{Environ: []string{"APP_PORT=90", "APP_NAME=env"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 80, csp.PropertyInt("app.port", 0))
+
+	s.fail = true
+	_, err = csp.PropertyIntE("app.port", 0)
+	se := &SourceError{}
+	assert.True(t, errors.As(err, &se))
+	assert.Equal(t, "failing", se.Source)
+	assert.Equal(t, "app.port", se.Property)
+	assert.Equal(t, 1, csp.PropertyInt("app.port", 1))
+	assert.Equal(t, "NO_VALUE", csp.Property("app.name", "NO_VALUE"))
+
+	csp.SetSourceErrorPolicy("failing", ErrorPolicySkip)
+	assert.Equal(t, 90, csp.PropertyInt("app.port", 0))
+	value, err := csp.PropertyE("app.name", "NO_VALUE")
+	assert.Nil(t, err)
+	assert.Equal(t, "env", value)
+
+	csp.SetSourceErrorPolicy("failing", ErrorPolicyLastKnown)
+	s.fail = false
+	assert.Equal(t, 80, csp.PropertyInt("app.port", 0))
+	s.fail = true
+	assert.Equal(t, 80, csp.PropertyInt("app.port", 0))
+	assert.Equal(t, "env", csp.Property("app.name", "NO_VALUE"))
+}
+
+func TestInvalidValue(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: []string{"APP_PORT=abc"}})
+	assert.Nil(t, err)
+
+	value, err := csp.PropertyIntE("app.port", 10)
+	assert.NotNil(t, err)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, 10, csp.PropertyInt("app.port", 10))
+
+	_, err = csp.PropertyBoolE("app.port", false)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FileConfigSource writable configuration source backed by the file. The format of the file
+// is selected by the extension, `.yaml` and `.yml` files are yaml files and all other files
+// are properties files with the `key=value` lines. The changes are written atomically and the
+// yaml file is written with the flat property names.
+type FileConfigSource struct {
+	// Path of the file, the file is created with the first change
+	Path string
+	// Prio priority of the configuration source
+	Prio    int
+	mutex   sync.RWMutex
+	data    map[string]string
+	version uint64
+}
+
+// AddFile adds writable file configuration source to the default provider
+func AddFile(path string, priority int) error {
+	return Default.AddFile(path, priority)
+}
+
+// AddFile adds writable file configuration source
+func (c *ConfigSourceProvider) AddFile(path string, priority int) error {
+	return c.Add(&FileConfigSource{Path: path, Prio: priority})
+}
+
+func (f *FileConfigSource) Init() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.data = map[string]string{}
+	f.version++
+
+	d, err := os.ReadFile(f.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if f.isYaml() {
+		tmp := map[string]interface{}{}
+		err = yaml.Unmarshal(d, &tmp)
+		if err != nil {
+			return err
+		}
+		flatten(tmp, "", f.data)
+		return nil
+	}
+	return parseProperties(d, f.data)
+}
+
+func (f *FileConfigSource) Priority() int {
+	return f.Prio
+}
+
+// Name of the configuration source with the path, `file:<path>`
+func (f *FileConfigSource) Name() string {
+	return `file:` + f.Path
+}
+
+func (f *FileConfigSource) Property(name string) (string, bool, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	v, e := f.data[name]
+	return v, e, nil
+}
+
+func (f *FileConfigSource) Properties() (map[string]string, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	tmp := make(map[string]string, len(f.data))
+	for k, v := range f.data {
+		tmp[k] = v
+	}
+	return tmp, nil
+}
+
+// Set the property value and write the file
+func (f *FileConfigSource) Set(name, value string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	previous, exists := f.data[name]
+	f.data[name] = value
+	err := f.write()
+	if err != nil {
+		if exists {
+			f.data[name] = previous
+		} else {
+			delete(f.data, name)
+		}
+		return err
+	}
+	f.version++
+	return nil
+}
+
+// Delete the property and write the file
+func (f *FileConfigSource) Delete(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	previous, exists := f.data[name]
+	if !exists {
+		return nil
+	}
+	delete(f.data, name)
+	err := f.write()
+	if err != nil {
+		f.data[name] = previous
+		return err
+	}
+	f.version++
+	return nil
+}
+
+// Version of the properties, changed after every change of the file
+func (f *FileConfigSource) Version() uint64 {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.version
+}
+
+func (f *FileConfigSource) isYaml() bool {
+	ext := strings.ToLower(filepath.Ext(f.Path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// write the data to the temporary file and rename it to the file
+func (f *FileConfigSource) write() error {
+	var d []byte
+	if f.isYaml() {
+		tmp, err := yaml.Marshal(f.data)
+		if err != nil {
+			return err
+		}
+		d = tmp
+	} else {
+		d = formatProperties(f.data)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), "."+filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(d)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+var propertiesEscape = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r", "=", "\\=", ":", "\\:")
+
+// parseProperties parse the properties file lines `key=value` or `key: value`
+func parseProperties(d []byte, data map[string]string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(d))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		key, value := splitProperty(line)
+		data[unescapeProperty(strings.TrimSpace(key))] = unescapeProperty(strings.TrimSpace(value))
+	}
+	return scanner.Err()
+}
+
+// splitProperty split the line by the first unescaped `=` or `:`
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], line[i+1:]
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	b := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if ch == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 'n':
+				ch = '\n'
+			case 'r':
+				ch = '\r'
+			case 't':
+				ch = '\t'
+			default:
+				ch = value[i]
+			}
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// formatProperties format the properties sorted by the name
+func formatProperties(data map[string]string) []byte {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := bytes.Buffer{}
+	for _, k := range keys {
+		b.WriteString(propertiesEscape.Replace(k))
+		b.WriteString("=")
+		b.WriteString(propertiesEscape.Replace(data[k]))
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileConfigSource(t *testing.T) {
+	for _, name := range []string{"runtime.properties", "runtime.yaml"} {
+		path := filepath.Join(t.TempDir(), name)
+
+		f := &FileConfigSource{Path: path, Prio: 400}
+		err := f.Init()
+		assert.Nil(t, err)
+		assert.Nil(t, f.Set("app.maintenance", "true"))
+		assert.Nil(t, f.Set("app.message", "a=b: c\nd \\ e"))
+		assert.Nil(t, f.Set("app.rate-limit", "100"))
+		assert.Nil(t, f.Delete("app.rate-limit"))
+		assert.Nil(t, f.Delete("app.none"))
+
+		// read after restart
+		f = &FileConfigSource{Path: path, Prio: 400}
+		err = f.Init()
+		assert.Nil(t, err)
+		properties, err := f.Properties()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{
+			"app.maintenance": "true",
+			"app.message":     "a=b: c\nd \\ e",
+		}, properties, name)
+
+		files, err := os.ReadDir(filepath.Dir(path))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(files), name)
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	data := map[string]string{}
+	err := parseProperties([]byte("# comment\n! comment\n\napp.name = test\napp.port: 80\napp\\=key=value\napp.empty\n"), data)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"app.name":  "test",
+		"app.port":  "80",
+		"app=key":   "value",
+		"app.empty": "",
+	}, data)
+}
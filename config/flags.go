@@ -2,14 +2,37 @@ package config
 
 import (
 	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+// FlagsConfigSource command line flags configuration source. The flags and the positional arguments
+// may be interspersed, `migrate --steps 3 up` has the positional arguments `migrate` and `up`, all
+// arguments after the `--` terminator are positional. The source is safe for concurrent use,
+// the arguments are parsed again when the properties are registered.
 type FlagsConfigSource struct {
-	cmd   []string
-	args  []string
-	flags map[string]string
+	// Args command line arguments without the program name, default os.Args[1:]
+	Args []string
+	// Strict rejects the unknown flags in the Validate method
+	Strict bool
+	// StdFlags flags of the standard library flag package which are known flags, default flag.CommandLine
+	StdFlags   *flag.FlagSet
+	mutex      sync.RWMutex
+	cmd        []string
+	args       []string
+	values     map[string][]string
+	flags      map[string]string
+	known      map[string]PropertyDescription
+	aliases    map[string]string
+	positional []string
+	rest       []string
 }
 
 func (f *FlagsConfigSource) Priority() int {
@@ -21,23 +44,265 @@ func (f *FlagsConfigSource) Name() string {
 }
 
 func (f *FlagsConfigSource) Init() error {
-	f.cmd = os.Args[1:]
-	f.flags = map[string]string{}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.cmd = f.Args
+	if f.cmd == nil {
+		f.cmd = os.Args[1:]
+	}
 	return f.parseArgs()
 }
 
 func (f *FlagsConfigSource) Property(name string) (string, bool, error) {
-	tmp := strings.ReplaceAll(name, ".", "-")
-	v, e := f.flags[tmp]
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	v, e := f.flags[FlagName(name)]
 	return v, e, nil
 }
 
+// FlagName command line flag name of the property
+func FlagName(name string) string {
+	return strings.ReplaceAll(name, ".", "-")
+}
+
+// Properties all flags, the map is replaced and never changed when the arguments are parsed again
 func (f *FlagsConfigSource) Properties() (map[string]string, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
 	return f.flags, nil
 }
 
+// RegisterProperties register the known flags and the short aliases of the properties
+func (f *FlagsConfigSource) RegisterProperties(items ...PropertyDescription) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.known == nil {
+		f.known = map[string]PropertyDescription{}
+	}
+	for _, item := range items {
+		f.known[FlagName(item.Key)] = item
+		if len(item.Short) > 0 {
+			f.alias(item.Short, item.Key)
+		}
+	}
+	f.reparse()
+}
+
+// Alias register the short flag `-short` of the property
+func (f *FlagsConfigSource) Alias(short, name string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.alias(short, name)
+	f.reparse()
+}
+
+// Positional returns the arguments which are not flags or values of the flags,
+// the arguments between the flags and all arguments after the `--` terminator
+func (f *FlagsConfigSource) Positional() []string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.positional
+}
+
+// Rest returns the arguments after the `--` terminator
+func (f *FlagsConfigSource) Rest() []string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.rest
+}
+
+func (f *FlagsConfigSource) alias(short, name string) {
+	if f.aliases == nil {
+		f.aliases = map[string]string{}
+	}
+	f.aliases[short] = FlagName(name)
+}
+
+// reparse parse the arguments again after the source was initialized, the caller holds the lock
+func (f *FlagsConfigSource) reparse() {
+	if f.flags == nil {
+		return
+	}
+	// the syntax errors are reported by the Init method
+	_ = f.parseArgs()
+}
+
+// Help returns true if the `-h` or `--help` flag is used and it is not a registered property
+func (f *FlagsConfigSource) Help() bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	for _, name := range []string{"h", "help"} {
+		if _, exists := f.flags[name]; exists {
+			if _, known := f.known[name]; !known {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate check the values of the known flags and in the strict mode rejects the unknown flags
+func (f *FlagsConfigSource) Validate() error {
+	return f.validate(f.Strict)
+}
+
+// ValidateValues check the values of the known flags, the unknown flags are not rejected
+// because the properties of the structures bound later are not known yet
+func (f *FlagsConfigSource) ValidateValues() error {
+	return f.validate(false)
+}
+
+func (f *FlagsConfigSource) validate(strict bool) error {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	names := make([]string, 0, len(f.values))
+	for name := range f.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		item, known := f.knownFlag(name)
+		if !known {
+			if strict && !f.isStdFlag(name) && name != "h" && name != "help" {
+				return errors.New("unknown flag: --" + name)
+			}
+			continue
+		}
+		for _, value := range f.values[name] {
+			err := checkValue(item.Type, value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for flag --%s: %w", value, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Usage writes the usage of all known flags
+func (f *FlagsConfigSource) Usage(w io.Writer) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	fmt.Fprintf(w, "Usage of %s:\n", programName())
+	names := make([]string, 0, len(f.known))
+	for name := range f.known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	shorts := map[string]string{}
+	for short, name := range f.aliases {
+		shorts[name] = short
+	}
+	for _, name := range names {
+		item := f.known[name]
+		line := "  --" + name
+		if short, exists := shorts[name]; exists {
+			line = "  -" + short + ", --" + name
+		}
+		if item.Type != "bool" {
+			line += " " + item.Type
+		}
+		line += "\n    \t"
+		if len(item.Description) > 0 {
+			line += strings.ReplaceAll(item.Description, "\n", "\n    \t") + " "
+		}
+		if !isZeroDefault(item.Default) {
+			line += "(default " + item.Default + ") "
+		}
+		if len(item.Env) > 0 {
+			line += "(env " + item.Env + ")"
+		}
+		fmt.Fprintln(w, strings.TrimSuffix(line, " "))
+	}
+
+	std := f.stdFlags()
+	if std == nil {
+		return
+	}
+	std.VisitAll(func(fl *flag.Flag) {
+		if _, known := f.known[fl.Name]; known {
+			return
+		}
+		line := "  -" + fl.Name
+		name, usage := flag.UnquoteUsage(fl)
+		if len(name) > 0 {
+			line += " " + name
+		}
+		line += "\n    \t" + strings.ReplaceAll(usage, "\n", "\n    \t")
+		if !isZeroDefault(fl.DefValue) {
+			line += " (default " + fl.DefValue + ")"
+		}
+		fmt.Fprintln(w, line)
+	})
+}
+
+// knownFlag find the registered property of the flag by the flag name or the canonical name
+func (f *FlagsConfigSource) knownFlag(name string) (PropertyDescription, bool) {
+	item, known := f.known[name]
+	if known {
+		return item, true
+	}
+	canonical := CanonicalKey(name)
+	if strings.HasPrefix(canonical, "+") {
+		canonical = canonical[strings.Index(canonical, ".")+1:]
+	}
+	for _, item := range f.known {
+		if CanonicalKey(item.Key) == canonical {
+			return item, true
+		}
+	}
+	return PropertyDescription{}, false
+}
+
+func (f *FlagsConfigSource) stdFlags() *flag.FlagSet {
+	if f.StdFlags != nil {
+		return f.StdFlags
+	}
+	return flag.CommandLine
+}
+
+func (f *FlagsConfigSource) isStdFlag(name string) bool {
+	std := f.stdFlags()
+	return std != nil && std.Lookup(name) != nil
+}
+
+func isZeroDefault(value string) bool {
+	return value == "" || value == "false" || value == "0"
+}
+
+func programName() string {
+	if len(os.Args) > 0 {
+		return os.Args[0]
+	}
+	return "gluon"
+}
+
+// checkValue check the value of the property type
+func checkValue(typeName, value string) error {
+	var err error
+	switch typeName {
+	case "bool":
+		if len(value) > 0 {
+			_, err = strconv.ParseBool(value)
+		}
+	case "int", "int16", "int32", "int64":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "float32", "float64":
+		_, err = strconv.ParseFloat(value, 64)
+	case "time.Duration":
+		_, err = time.ParseDuration(value)
+	}
+	return err
+}
+
+// parseArgs parse the command line arguments, the arguments are parsed again when the known
+// flags or the aliases change because the boolean flags do not consume the next argument.
+// The caller holds the write lock.
 func (f *FlagsConfigSource) parseArgs() error {
 	f.args = f.cmd
+	f.values = map[string][]string{}
+	f.positional = nil
+	f.rest = nil
 	for {
 		seen, err := f.parseOne()
 		if seen {
@@ -48,6 +313,16 @@ func (f *FlagsConfigSource) parseArgs() error {
 		}
 		return err
 	}
+
+	f.flags = map[string]string{}
+	for name, values := range f.values {
+		f.flags[name] = values[len(values)-1]
+		if len(values) > 1 {
+			for i, value := range values {
+				f.flags[flattenArray(name, i)] = value
+			}
+		}
+	}
 	return nil
 }
 
@@ -56,15 +331,22 @@ func (f *FlagsConfigSource) parseOne() (bool, error) {
 		return false, nil
 	}
 	s := f.args[0]
+	f.args = f.args[1:]
+	if f.rest != nil {
+		f.rest = append(f.rest, s)
+		f.positional = append(f.positional, s)
+		return true, nil
+	}
 	if len(s) < 2 || s[0] != '-' {
-		return false, nil
+		f.positional = append(f.positional, s)
+		return true, nil
 	}
 	numMinuses := 1
 	if s[1] == '-' {
 		numMinuses++
 		if len(s) == 2 { // "--" terminates the flags
-			f.args = f.args[1:]
-			return false, nil
+			f.rest = []string{}
+			return true, nil
 		}
 	}
 	name := s[numMinuses:]
@@ -73,7 +355,6 @@ func (f *FlagsConfigSource) parseOne() (bool, error) {
 	}
 
 	// it's a flag. does it have an argument?
-	f.args = f.args[1:]
 	hasValue := false
 	value := ""
 	for i := 1; i < len(name); i++ { // equals cannot be first
@@ -84,17 +365,48 @@ func (f *FlagsConfigSource) parseOne() (bool, error) {
 			break
 		}
 	}
+	if alias, exists := f.aliases[name]; exists && numMinuses == 1 {
+		name = alias
+	}
 
-	// It must have a value, which might be the next argument.
-	if !hasValue && len(f.args) > 0 {
+	switch {
+	case hasValue:
+	case strings.HasPrefix(name, "no-") && len(name) > 3 && !f.isKnown(name):
+		// "--no-x" is the false value of the flag "--x"
+		name = name[3:]
+		value = "false"
+	case f.isBool(name):
+		value = "true"
+	case len(f.args) > 0 && len(f.args[0]) > 0 && f.args[0][0] != '-':
 		// value is the next arg
-		tmp := f.args[0]
-		if len(tmp) > 0 && tmp[0] != '-' {
-			value = tmp
-			f.args = f.args[1:]
-		}
+		value = f.args[0]
+		f.args = f.args[1:]
+	default:
+		value = "true"
 	}
-	f.flags[name] = value
+	f.values[name] = append(f.values[name], value)
 
 	return true, nil
 }
+
+// isKnown returns true for the registered flags and the flags of the standard library
+func (f *FlagsConfigSource) isKnown(name string) bool {
+	_, known := f.knownFlag(name)
+	return known || f.isStdFlag(name)
+}
+
+// isBool returns true if the known flag is boolean flag
+func (f *FlagsConfigSource) isBool(name string) bool {
+	if item, known := f.knownFlag(name); known {
+		return item.Type == "bool"
+	}
+	std := f.stdFlags()
+	if std == nil {
+		return false
+	}
+	if fl := std.Lookup(name); fl != nil {
+		b, ok := fl.Value.(interface{ IsBoolFlag() bool })
+		return ok && b.IsBoolFlag()
+	}
+	return false
+}
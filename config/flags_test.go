@@ -1 +1,163 @@
 package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type FlagsStruct struct {
+	Port    int    `config:"port" description:"Listen port"`
+	Verbose bool   `config:"verbose"`
+	Name    string `config:"name"`
+}
+
+func TestFlagsRegistration(t *testing.T) {
+	std := flag.NewFlagSet("test", flag.ContinueOnError)
+	std.String("std", "value", "standard `flag`")
+
+	f := &FlagsConfigSource{Args: []string{"--gluon-server-port=8080", "-std", "x"}, StdFlags: std, Strict: true}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(f)
+	assert.Nil(t, err)
+	assert.False(t, f.Help())
+
+	// unknown before the structure is bound
+	assert.EqualError(t, f.Validate(), "unknown flag: --gluon-server-port")
+
+	s := &FlagsStruct{Port: 80}
+	err = csp.Extension("server", s)
+	assert.Nil(t, err)
+	assert.Equal(t, 8080, s.Port)
+	assert.Nil(t, f.Validate())
+
+	b := &strings.Builder{}
+	f.Usage(b)
+	assert.Contains(t, b.String(), "  --gluon-server-port int\n    \tListen port (default 80) (env GLUON_SERVER_PORT)\n")
+	assert.Contains(t, b.String(), "  --gluon-server-verbose\n    \t(env GLUON_SERVER_VERBOSE)\n")
+	assert.Contains(t, b.String(), "  -std flag\n    \tstandard flag (default value)\n")
+}
+
+func TestFlagsValidate(t *testing.T) {
+	f := &FlagsConfigSource{Args: []string{"--gluon-server-port=abc", "--gluon-server-unknown=1", "--help"}, StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(f)
+	assert.Nil(t, err)
+	assert.True(t, f.Help())
+
+	items, err := csp.DescribeExtension("server", &FlagsStruct{})
+	assert.Nil(t, err)
+	csp.RegisterProperties(items...)
+	assert.EqualError(t, f.Validate(), `invalid value "abc" for flag --gluon-server-port: strconv.ParseInt: parsing "abc": invalid syntax`)
+
+	f = &FlagsConfigSource{Args: []string{"--gluon-server-unknown=1", "--gluon.server.port=1"}, StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	err = csp.Add(f)
+	assert.Nil(t, err)
+	f.RegisterProperties(items...)
+	assert.Nil(t, f.Validate())
+	f.Strict = true
+	assert.EqualError(t, f.Validate(), "unknown flag: --gluon-server-unknown")
+}
+
+type FlagsListStruct struct {
+	Verbose bool     `config:"verbose" short:"v"`
+	Cache   bool     `config:"cache"`
+	Tags    []string `config:"tags" short:"t"`
+	Port    int      `config:"port"`
+}
+
+func TestFlagsBoolean(t *testing.T) {
+	f := &FlagsConfigSource{Args: []string{"--gluon-app-verbose", "--gluon-app-debug", "--no-gluon-app-cache", "--gluon-app-port", "80"}, StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(f)
+	assert.Nil(t, err)
+
+	assert.True(t, csp.PropertyBool("gluon.app.verbose", false))
+	assert.True(t, csp.PropertyBool("gluon.app.debug", false))
+	assert.False(t, csp.PropertyBool("gluon.app.cache", true))
+	assert.Equal(t, 80, csp.PropertyInt("gluon.app.port", 0))
+}
+
+func TestFlagsRepeatedAndShort(t *testing.T) {
+	f := &FlagsConfigSource{Args: []string{"-v", "serve", "-t", "a", "--gluon-app-tags", "b", "--gluon-app-port=1", "--", "--gluon-app-port=2", "x"},
+		StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b"}, csp.PropertyStrings("gluon.app.tags", nil))
+
+	s := &FlagsListStruct{}
+	err = csp.Extension("app", s)
+	assert.Nil(t, err)
+	assert.True(t, s.Verbose)
+	assert.Equal(t, []string{"a", "b"}, s.Tags)
+	assert.Equal(t, 1, s.Port)
+	assert.Equal(t, []string{"serve", "--gluon-app-port=2", "x"}, f.Positional())
+	assert.Equal(t, []string{"--gluon-app-port=2", "x"}, f.Rest())
+
+	f.Strict = true
+	assert.Nil(t, f.Validate())
+
+	b := &strings.Builder{}
+	f.Usage(b)
+	assert.Contains(t, b.String(), "  -v, --gluon-app-verbose\n")
+	assert.Contains(t, b.String(), "  -t, --gluon-app-tags []string\n")
+}
+
+func TestFlagsAlias(t *testing.T) {
+	f := &FlagsConfigSource{Args: []string{"-p", "8080", "--gluon-app-verbose", "run"}, StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(f)
+	assert.Nil(t, err)
+	assert.Equal(t, "run", csp.Property("gluon.app.verbose", ""))
+
+	f.Alias("p", "gluon.app.port")
+	csp.RegisterProperties(PropertyDescription{Key: "gluon.app.verbose", Type: "bool"})
+	assert.Equal(t, 8080, csp.PropertyInt("gluon.app.port", 0))
+	assert.True(t, csp.PropertyBool("gluon.app.verbose", false))
+	assert.Equal(t, []string{"run"}, f.Positional())
+	assert.Nil(t, f.Rest())
+}
+
+func TestPropertyStringsSingleSource(t *testing.T) {
+	f := &FlagsConfigSource{Args: []string{"--tags", "x"}, StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	file := filepath.Join(t.TempDir(), "app.properties")
+	err := os.WriteFile(file, []byte("tags[0]=a\ntags[1]=b\n"), 0600)
+	assert.Nil(t, err)
+	csp := &ConfigSourceProvider{}
+	err = csp.Add(f, &FileConfigSource{Path: file, Prio: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"x"}, csp.PropertyStrings("tags", nil))
+}
+
+func TestFlagsInterspersed(t *testing.T) {
+	f := &FlagsConfigSource{Args: []string{"migrate", "--steps", "3", "up", "--", "--raw"}, StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	err := f.Init()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"migrate", "up", "--raw"}, f.Positional())
+	assert.Equal(t, []string{"--raw"}, f.Rest())
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		f.RegisterProperties(PropertyDescription{Key: "steps", Type: "int"})
+	}()
+	value, _, _ := f.Property("steps")
+	<-done
+	assert.Equal(t, "3", value)
+}
+
+func TestFlagsPropertyMap(t *testing.T) {
+	f := &FlagsConfigSource{Args: []string{"--gluon-log-category-orders-level=debug", "--gluon-log-level=warn"}, StdFlags: flag.NewFlagSet("test", flag.ContinueOnError)}
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(f)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"orders.level": "debug"}, csp.PropertyMap("gluon.log.category", nil))
+
+	csp.SetRelaxedKeys(false)
+	assert.Nil(t, csp.PropertyMap("gluon.log.category", nil))
+}
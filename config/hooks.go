@@ -0,0 +1,130 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Defaulter is implemented by the configuration structures which compute default values
+// after the properties are bound
+type Defaulter interface {
+	// SetDefaults set computed default values
+	SetDefaults()
+}
+
+// Validator is implemented by the configuration structures which validate the values
+// after the properties are bound and the defaults are set
+type Validator interface {
+	// Validate validates the configuration values
+	Validate() error
+}
+
+// FieldError validation error of the configuration structure
+type FieldError struct {
+	// Field path of the structure field, empty for the root structure
+	Field string
+	// Property name of the structure property
+	Property string
+	// Err validation error
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	if len(e.Field) == 0 {
+		return e.Err.Error()
+	}
+	return e.Field + " (" + e.Property + "): " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors all validation errors of the configuration structure
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	tmp := make([]string, len(e))
+	for i, item := range e {
+		tmp[i] = item.Error()
+	}
+	return "configuration validation failed: " + strings.Join(tmp, "; ")
+}
+
+// postBind call the lifecycle hooks of the bound structure and the nested structures
+func (c *ConfigSourceProvider) postBind(prefix string, value interface{}) error {
+	original := reflect.ValueOf(value)
+	c.callHooks(original, "", prefix, func(v interface{}, path, prop string) {
+		if d, ok := v.(Defaulter); ok {
+			d.SetDefaults()
+		}
+	})
+
+	errs := ValidationErrors{}
+	c.callHooks(original, "", prefix, func(v interface{}, path, prop string) {
+		if d, ok := v.(Validator); ok {
+			err := d.Validate()
+			if err != nil {
+				errs = append(errs, &FieldError{Field: path, Property: prop, Err: err})
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// callHooks call the hook on the nested structures first and then on the structure itself
+func (c *ConfigSourceProvider) callHooks(value reflect.Value, path, prefix string, hook func(v interface{}, path, prop string)) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	c.nestedHooks(value, path, prefix, hook)
+	if value.CanAddr() && value.Addr().CanInterface() {
+		hook(value.Addr().Interface(), path, prefix)
+	}
+}
+
+// nestedHooks call the hooks of the nested structures, the hooks of the squashed
+// embedded structures are promoted to the parent structure. The fields are skipped
+// by the same rules as the bound fields.
+func (c *ConfigSourceProvider) nestedHooks(value reflect.Value, path, prefix string, hook func(v interface{}, path, prop string)) {
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	typeof := value.Type()
+	for i := 0; i < typeof.NumField(); i++ {
+		f := typeof.Field(i)
+		field := value.Field(i)
+		tag, hasTag := f.Tag.Lookup("config")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && !hasTag && field.Kind() == reflect.Struct {
+			c.nestedHooks(field, fieldPath(path, f.Name), prefix, hook)
+			continue
+		}
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+		if field.Kind() == reflect.Interface && !field.IsNil() && field.Elem().Kind() == reflect.Ptr {
+			c.callHooks(field.Elem(), fieldPath(path, f.Name), c.propertyName(prefix, f), hook)
+			continue
+		}
+		if field.Kind() != reflect.Struct || !field.CanAddr() {
+			continue
+		}
+		c.callHooks(field.Addr(), fieldPath(path, f.Name), c.propertyName(prefix, f), hook)
+	}
+}
+
+func fieldPath(path, name string) string {
+	if len(path) > 0 {
+		return path + "." + name
+	}
+	return name
+}
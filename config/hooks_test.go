@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TLSConfig struct {
+	Enabled bool   `config:"enabled"`
+	Cert    string `config:"cert"`
+}
+
+func (t *TLSConfig) Validate() error {
+	if t.Enabled && len(t.Cert) == 0 {
+		return errors.New("cert is required when tls is enabled")
+	}
+	return nil
+}
+
+type ServerConfig struct {
+	Host    string    `config:"host"`
+	Port    int       `config:"port"`
+	Address string    `config:"address"`
+	TLS     TLSConfig `config:"tls"`
+}
+
+func (s *ServerConfig) SetDefaults() {
+	if len(s.Address) == 0 {
+		s.Address = s.Host + ":" + toString(s.Port)
+	}
+}
+
+func (s *ServerConfig) Validate() error {
+	if s.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: []string{
+		"GLUON_SERVER_HOST=localhost",
+		"GLUON_SERVER_PORT=8080",
+	}})
+	assert.Nil(t, err)
+
+	s := &ServerConfig{}
+	err = csp.Extension("server", s)
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:8080", s.Address)
+
+	csp = &ConfigSourceProvider{}
+	err = csp.Add(&EnvConfigSource{Environ: []string{
+		"GLUON_SERVER_TLS_ENABLED=true",
+	}})
+	assert.Nil(t, err)
+
+	s = &ServerConfig{}
+	err = csp.Extension("server", s)
+	errs := ValidationErrors{}
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "TLS", errs[0].Field)
+	assert.Equal(t, "gluon.server.tls", errs[0].Property)
+	assert.Equal(t, "", errs[1].Field)
+	assert.Equal(t, "gluon.server", errs[1].Property)
+	assert.Equal(t, "configuration validation failed: TLS (gluon.server.tls): cert is required when tls is enabled; port must be positive", err.Error())
+}
+
+type secretConfig struct {
+	User string `config:"user"`
+}
+
+func (c *secretConfig) Validate() error {
+	return errors.New("credentials are not bound")
+}
+
+type embeddedServer struct {
+	Server ServerConfig `config:"server"`
+}
+
+type unexportedConfig struct {
+	secretConfig `config:"cred"`
+	embeddedServer
+	Name string `config:"name"`
+}
+
+func TestHooksUnexported(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: []string{"NAME=app", "SERVER_PORT=8080"}})
+	assert.Nil(t, err)
+
+	s := &unexportedConfig{}
+	assert.NotPanics(t, func() {
+		err = csp.Properties(s)
+	})
+	// only the promoted method of the unexported structure is called
+	assert.EqualError(t, err, "configuration validation failed: credentials are not bound")
+	assert.Equal(t, "app", s.Name)
+}
@@ -0,0 +1,316 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/go-gluon/gluon/log"
+)
+
+// ErrNoMutableSource no mutable configuration source is added to the provider
+var ErrNoMutableSource = errors.New("No mutable configuration source")
+
+// MutableConfigSource configuration source which supports changes of the properties
+type MutableConfigSource interface {
+	ConfigSource
+	// Set the property value
+	Set(name, value string) error
+	// Delete the property
+	Delete(name string) error
+}
+
+// ChangeEvent change of the property
+type ChangeEvent struct {
+	// Name of the changed property
+	Name string
+	// Value current value of the property resolved from all configuration sources
+	Value string
+	// Exists returns true if any configuration source provides the property after the change
+	Exists bool
+	// Source name of the mutable configuration source which was changed
+	Source string
+}
+
+// Binding struct bound again after every change of the properties done by the provider.
+// The properties are bound into the fresh copy of the struct values from the time of the Bind call,
+// so the deleted property gets its initial value back. The copy is validated and replaces the struct
+// under the write lock of the binding, read the struct concurrently with the changes only under RLock.
+type Binding struct {
+	provider *ConfigSourceProvider
+	prefix   string
+	target   reflect.Value
+	defaults reflect.Value
+	mutex    sync.RWMutex
+}
+
+// changes subscribers and bindings of the provider
+type changes struct {
+	mutex       sync.Mutex
+	change      sync.Mutex
+	bindings    []*Binding
+	subscribers map[int]func(e ChangeEvent)
+	next        int
+}
+
+// Set the property in the mutable configuration source of the default provider
+func Set(name, value string) error {
+	return Default.Set(name, value)
+}
+
+// Delete the property in the mutable configuration source of the default provider
+func Delete(name string) error {
+	return Default.Delete(name)
+}
+
+// Bind the properties to the struct in the default provider and bind them again after the changes
+func Bind(value interface{}) (*Binding, error) {
+	return Default.Bind(value)
+}
+
+// BindExtension bind the extension properties to the struct in the default provider
+// and bind them again after the changes
+func BindExtension(name string, value interface{}) (*Binding, error) {
+	return Default.BindExtension(name, value)
+}
+
+// Subscribe to the property changes of the default provider
+func Subscribe(fn func(e ChangeEvent)) func() {
+	return Default.Subscribe(fn)
+}
+
+// Set the property in the mutable configuration source with the highest ordinal. The bound
+// structs are bound again and the subscribers are notified. The configuration sources with
+// higher ordinal still override the value. The change is rolled back and the error is returned
+// if any bound struct is not valid with the new value.
+func (c *ConfigSourceProvider) Set(name, value string) error {
+	return c.change(name, func(s MutableConfigSource) error {
+		return s.Set(name, value)
+	})
+}
+
+// Delete the property in the mutable configuration source with the highest ordinal. The bound
+// structs are bound again and the subscribers are notified. The fields of the deleted property
+// get the value from the time of the Bind call unless the other configuration source provides it.
+func (c *ConfigSourceProvider) Delete(name string) error {
+	return c.change(name, func(s MutableConfigSource) error {
+		return s.Delete(name)
+	})
+}
+
+// Bind the properties to the struct like Properties and bind them again after every change
+// of the properties done by the provider until the binding is released
+func (c *ConfigSourceProvider) Bind(value interface{}) (*Binding, error) {
+	return c.addBinding("", value)
+}
+
+// BindExtension bind the properties to the struct like Extension and bind them again after
+// every change of the properties done by the provider until the binding is released
+func (c *ConfigSourceProvider) BindExtension(name string, value interface{}) (*Binding, error) {
+	return c.addBinding(configPrefix+name, value)
+}
+
+// addBinding bind the struct and register the binding
+func (c *ConfigSourceProvider) addBinding(prefix string, value interface{}) (*Binding, error) {
+	original := reflect.ValueOf(value)
+	if original.Kind() != reflect.Ptr || original.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("Configuration properties is not pointer to struct")
+	}
+	b := &Binding{provider: c, prefix: prefix, target: original, defaults: deepCopy(original.Elem())}
+	err := c.bindValue(prefix, value)
+	if err != nil {
+		return nil, err
+	}
+	c.changes.mutex.Lock()
+	defer c.changes.mutex.Unlock()
+	c.changes.bindings = append(c.changes.bindings, b)
+	return b, nil
+}
+
+// RLock lock the bound struct for reading
+func (b *Binding) RLock() {
+	b.mutex.RLock()
+}
+
+// RUnlock unlock the bound struct locked for reading
+func (b *Binding) RUnlock() {
+	b.mutex.RUnlock()
+}
+
+// Release stop binding the struct after the changes
+func (b *Binding) Release() {
+	c := b.provider
+	c.changes.mutex.Lock()
+	defer c.changes.mutex.Unlock()
+	for i, item := range c.changes.bindings {
+		if item == b {
+			c.changes.bindings = append(c.changes.bindings[:i], c.changes.bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+// rebind bind the properties into the fresh copy of the initial struct values
+func (b *Binding) rebind() (reflect.Value, error) {
+	fresh := reflect.New(b.defaults.Type())
+	fresh.Elem().Set(deepCopy(b.defaults))
+	err := b.provider.properties(b.prefix, fresh.Interface())
+	if err == nil {
+		err = b.provider.postBind(b.prefix, fresh.Interface())
+	}
+	return fresh, err
+}
+
+// swap replace the bound struct by the fresh copy
+func (b *Binding) swap(fresh reflect.Value) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.target.Elem().Set(fresh.Elem())
+}
+
+// Subscribe call the function after every property change, returns the unsubscribe function
+func (c *ConfigSourceProvider) Subscribe(fn func(e ChangeEvent)) func() {
+	c.changes.mutex.Lock()
+	defer c.changes.mutex.Unlock()
+	if c.changes.subscribers == nil {
+		c.changes.subscribers = map[int]func(e ChangeEvent){}
+	}
+	id := c.changes.next
+	c.changes.next++
+	c.changes.subscribers[id] = fn
+	return func() {
+		c.changes.mutex.Lock()
+		defer c.changes.mutex.Unlock()
+		delete(c.changes.subscribers, id)
+	}
+}
+
+// change the property in the first mutable configuration source and notify about the change.
+// The changes are serialized, the change which breaks the validation of the bound structs is rolled back.
+func (c *ConfigSourceProvider) change(name string, fn func(s MutableConfigSource) error) error {
+	c.changes.change.Lock()
+	defer c.changes.change.Unlock()
+
+	var entry *sourceEntry
+	var source MutableConfigSource
+	for _, item := range c.entries() {
+		if tmp, ok := item.source.(MutableConfigSource); ok {
+			entry = item
+			source = tmp
+			break
+		}
+	}
+	if source == nil {
+		return ErrNoMutableSource
+	}
+
+	previous, existed, err := source.Property(name)
+	if err == nil {
+		err = fn(source)
+	}
+	if err != nil {
+		return &SourceError{Source: source.Name(), Property: name, Err: err}
+	}
+	c.reindex(entry)
+
+	c.changes.mutex.Lock()
+	bindings := make([]*Binding, len(c.changes.bindings))
+	copy(bindings, c.changes.bindings)
+	c.changes.mutex.Unlock()
+
+	values := make([]reflect.Value, len(bindings))
+	for i, b := range bindings {
+		values[i], err = b.rebind()
+		if err != nil {
+			c.rollback(entry, source, name, previous, existed)
+			return err
+		}
+	}
+	for i, b := range bindings {
+		b.swap(values[i])
+	}
+
+	value, exists, err := c.findProperty(name)
+	if err != nil {
+		return err
+	}
+	c.notify(ChangeEvent{Name: name, Value: value, Exists: exists, Source: source.Name()})
+	return nil
+}
+
+// rollback restore the previous value of the property in the mutable configuration source
+func (c *ConfigSourceProvider) rollback(entry *sourceEntry, source MutableConfigSource, name, previous string, existed bool) {
+	var err error
+	if existed {
+		err = source.Set(name, previous)
+	} else {
+		err = source.Delete(name)
+	}
+	c.reindex(entry)
+	if err != nil {
+		c.logger().Error("Rollback of the configuration change", log.Err(err).Add("property", name))
+	}
+}
+
+// deepCopy copy of the value with the copies of the pointers, interfaces, slices and maps
+// of the exported fields, the binding does not change the initial values
+func deepCopy(v reflect.Value) reflect.Value {
+	result := reflect.New(v.Type()).Elem()
+	result.Set(v)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			tmp := reflect.New(v.Type().Elem())
+			tmp.Elem().Set(deepCopy(v.Elem()))
+			result.Set(tmp)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			result.Set(deepCopy(v.Elem()))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if result.Field(i).CanSet() {
+				result.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			tmp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				tmp.Index(i).Set(deepCopy(v.Index(i)))
+			}
+			result.Set(tmp)
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			tmp := reflect.MakeMapWithSize(v.Type(), v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				tmp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+			result.Set(tmp)
+		}
+	}
+	return result
+}
+
+// notify the subscribers about the change
+func (c *ConfigSourceProvider) notify(e ChangeEvent) {
+	c.changes.mutex.Lock()
+	ids := make([]int, 0, len(c.changes.subscribers))
+	for id := range c.changes.subscribers {
+		ids = append(ids, id)
+	}
+	subscribers := make([]func(e ChangeEvent), 0, len(ids))
+	sort.Ints(ids)
+	for _, id := range ids {
+		subscribers = append(subscribers, c.changes.subscribers[id])
+	}
+	c.changes.mutex.Unlock()
+
+	for _, fn := range subscribers {
+		fn(e)
+	}
+}
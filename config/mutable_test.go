@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type RuntimeConfig struct {
+	Maintenance bool `config:"maintenance"`
+	RateLimit   int  `config:"rate-limit"`
+}
+
+func (r *RuntimeConfig) Validate() error {
+	if r.RateLimit < 0 {
+		return errors.New("negative rate limit")
+	}
+	return nil
+}
+
+func TestMutableConfigSource(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: []string{"GLUON_RUNTIME_RATE_LIMIT=10", "APP_NAME=env"}})
+	assert.Nil(t, err)
+	assert.Equal(t, ErrNoMutableSource, csp.Set("app.name", "x"))
+
+	path := filepath.Join(t.TempDir(), "runtime.properties")
+	err = csp.AddFile(path, 100)
+	assert.Nil(t, err)
+	source := "file:" + path
+
+	r := &RuntimeConfig{}
+	b, err := csp.BindExtension("runtime", r)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, r.RateLimit)
+
+	events := []ChangeEvent{}
+	unsubscribe := csp.Subscribe(func(e ChangeEvent) {
+		events = append(events, e)
+	})
+
+	err = csp.Set("gluon.runtime.maintenance", "true")
+	assert.Nil(t, err)
+	b.RLock()
+	assert.True(t, r.Maintenance)
+	b.RUnlock()
+
+	// environment variable has higher ordinal
+	err = csp.Set("gluon.runtime.rate-limit", "50")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, r.RateLimit)
+
+	// the deleted property gets the initial value
+	err = csp.Delete("gluon.runtime.maintenance")
+	assert.Nil(t, err)
+	assert.False(t, r.Maintenance)
+	assert.Equal(t, []ChangeEvent{
+		{Name: "gluon.runtime.maintenance", Value: "true", Exists: true, Source: source},
+		{Name: "gluon.runtime.rate-limit", Value: "10", Exists: true, Source: source},
+		{Name: "gluon.runtime.maintenance", Source: source},
+	}, events)
+
+	unsubscribe()
+	err = csp.Set("app.name", "file")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(events))
+
+	b.Release()
+	err = csp.Set("gluon.runtime.maintenance", "true")
+	assert.Nil(t, err)
+	assert.False(t, r.Maintenance)
+
+	// persisted
+	csp = &ConfigSourceProvider{}
+	err = csp.AddFile(path, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, csp.PropertyInt("gluon.runtime.rate-limit", 0))
+	assert.True(t, csp.PropertyBool("gluon.runtime.maintenance", false))
+}
+
+func TestMutableConfigSourceRollback(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	path := filepath.Join(t.TempDir(), "runtime.yaml")
+	err := csp.AddFile(path, 100)
+	assert.Nil(t, err)
+	err = csp.Set("gluon.runtime.rate-limit", "5")
+	assert.Nil(t, err)
+
+	r := &RuntimeConfig{}
+	_, err = csp.BindExtension("runtime", r)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, r.RateLimit)
+
+	err = csp.Set("gluon.runtime.rate-limit", "-1")
+	errs := ValidationErrors{}
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, 5, r.RateLimit)
+	assert.Equal(t, 5, csp.PropertyInt("gluon.runtime.rate-limit", 0))
+
+	csp = &ConfigSourceProvider{}
+	err = csp.AddFile(path, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, csp.PropertyInt("gluon.runtime.rate-limit", 0))
+}
+
+func TestDeepCopy(t *testing.T) {
+	type nested struct {
+		Items []string
+		Map   map[string]int
+		Ptr   *RuntimeConfig
+	}
+	v := nested{Items: []string{"a"}, Map: map[string]int{"a": 1}, Ptr: &RuntimeConfig{RateLimit: 1}}
+	tmp := deepCopy(reflect.ValueOf(v)).Interface().(nested)
+	tmp.Items[0] = "b"
+	tmp.Map["a"] = 2
+	tmp.Ptr.RateLimit = 2
+	assert.Equal(t, nested{Items: []string{"a"}, Map: map[string]int{"a": 1}, Ptr: &RuntimeConfig{RateLimit: 1}}, v)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy converts the Go field name to the property name for the fields without the `config` tag
+type NamingStrategy func(name string) string
+
+var (
+	// ExactNaming uses the Go field name as it is, `MaxPoolSize`
+	ExactNaming NamingStrategy = func(name string) string {
+		return name
+	}
+	// KebabCase naming strategy, `max-pool-size`
+	KebabCase NamingStrategy = func(name string) string {
+		return strings.ToLower(strings.Join(splitWords(name), "-"))
+	}
+	// SnakeCase naming strategy, `max_pool_size`
+	SnakeCase NamingStrategy = func(name string) string {
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+	}
+	// LowerCamelCase naming strategy, `maxPoolSize`
+	LowerCamelCase NamingStrategy = func(name string) string {
+		words := splitWords(name)
+		if len(words) > 0 {
+			words[0] = strings.ToLower(words[0])
+		}
+		return strings.Join(words, "")
+	}
+)
+
+// SetNamingStrategy set naming strategy of the fields without the `config` tag to the default provider
+func SetNamingStrategy(strategy NamingStrategy) {
+	Default.SetNamingStrategy(strategy)
+}
+
+// SetNamingStrategy set naming strategy of the fields without the `config` tag, default is ExactNaming
+func (c *ConfigSourceProvider) SetNamingStrategy(strategy NamingStrategy) {
+	c.naming = strategy
+}
+
+// fields call the function for all configuration fields of the struct. Unexported fields and
+// fields with the `config:"-"` tag are skipped, anonymous embedded structures without the tag
+// are squashed into the parent prefix.
+func (c *ConfigSourceProvider) fields(prefix string, original reflect.Value, fn func(f reflect.StructField, field reflect.Value, prop string)) {
+	if original.Kind() != reflect.Struct {
+		return
+	}
+	typeof := original.Type()
+	for i := 0; i < typeof.NumField(); i++ {
+		f := typeof.Field(i)
+		field := original.Field(i)
+		tag, hasTag := f.Tag.Lookup("config")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && !hasTag && field.Kind() == reflect.Struct {
+			c.fields(prefix, field, fn)
+			continue
+		}
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+		fn(f, field, c.propertyName(prefix, f))
+	}
+}
+
+// propertyName full property name of the struct field, the field without the tag is named
+// by the naming strategy also under the prefix
+func (c *ConfigSourceProvider) propertyName(prefix string, f reflect.StructField) string {
+	prop, ok := f.Tag.Lookup("config")
+	if !ok || len(prop) == 0 {
+		naming := c.naming
+		if naming == nil {
+			naming = ExactNaming
+		}
+		prop = naming(f.Name)
+	}
+	return flattenName(prefix, prop)
+}
+
+// splitWords split the Go identifier to the words, `HTTPServerPort` to `HTTP`, `Server`, `Port`
+func splitWords(name string) []string {
+	runes := []rune(name)
+	words := []string{}
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		if cur == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && unicode.IsLower(next))) {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
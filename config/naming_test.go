@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type PoolConfig struct {
+	MaxPoolSize int
+	MinIdle     int `config:"min"`
+}
+
+type credentials struct {
+	User string
+}
+
+type NamingStruct struct {
+	PoolConfig
+	credentials
+	HTTPServer string
+	DBUser     string
+	Ignored    string `config:"-"`
+	internal   string
+	Nested     struct {
+		UserID string
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	assert.Equal(t, []string{"Max", "Pool", "Size"}, splitWords("MaxPoolSize"))
+	assert.Equal(t, []string{"HTTP", "Server"}, splitWords("HTTPServer"))
+	assert.Equal(t, []string{"User", "ID"}, splitWords("UserID"))
+	assert.Equal(t, []string{"TLS"}, splitWords("TLS"))
+	assert.Equal(t, []string{"Port2", "Name"}, splitWords("Port2Name"))
+	assert.Equal(t, []string{"max", "pool"}, splitWords("max_pool"))
+
+	assert.Equal(t, "max-pool-size", KebabCase("MaxPoolSize"))
+	assert.Equal(t, "http_server", SnakeCase("HTTPServer"))
+	assert.Equal(t, "userID", LowerCamelCase("UserID"))
+	assert.Equal(t, "MaxPoolSize", ExactNaming("MaxPoolSize"))
+}
+
+func TestNamingStrategy(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	csp.SetNamingStrategy(KebabCase)
+	err := csp.Add(&EnvConfigSource{Environ: []string{
+		"GLUON_DB_MAX_POOL_SIZE=10",
+		"GLUON_DB_MIN=2",
+		"GLUON_DB_USER=admin",
+		"GLUON_DB_HTTP_SERVER=server",
+		"GLUON_DB_DB_USER=db",
+		"GLUON_DB_IGNORED=ignored",
+		"GLUON_DB_INTERNAL=internal",
+		"GLUON_DB_NESTED_USER_ID=id",
+	}})
+	assert.Nil(t, err)
+
+	s := &NamingStruct{Ignored: "default"}
+	err = csp.Extension("db", s)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, s.MaxPoolSize)
+	assert.Equal(t, 2, s.MinIdle)
+	assert.Equal(t, "admin", s.User)
+	assert.Equal(t, "server", s.HTTPServer)
+	assert.Equal(t, "db", s.DBUser)
+	assert.Equal(t, "default", s.Ignored)
+	assert.Equal(t, "", s.internal)
+	assert.Equal(t, "id", s.Nested.UserID)
+
+	items, err := csp.Describe(&NamingStruct{})
+	assert.Nil(t, err)
+	keys := []string{}
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+	assert.Equal(t, []string{"max-pool-size", "min", "user", "http-server", "db-user", "nested.user-id"}, keys)
+}
+
+type prefixConfig struct {
+	Host   string `config:"host"`
+	Port   int
+	Empty  string `config:""`
+	Nested struct {
+		Name string `config:"name"`
+	} `config:"nested"`
+}
+
+func TestPropertyNamePrefix(t *testing.T) {
+	// the untagged fields are named by the naming strategy also under the prefix,
+	// the baseline binder joined the empty tag to the prefix as `gluon.db.`
+	csp := &ConfigSourceProvider{}
+	items, err := csp.DescribeExtension("db", &prefixConfig{})
+	assert.Nil(t, err)
+	keys := []string{}
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+	assert.Equal(t, []string{"gluon.db.host", "gluon.db.Port", "gluon.db.Empty", "gluon.db.nested.name"}, keys)
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropertyInt64 int64 value property from the default configuration source provider
+func PropertyInt64(name string, defaultValue int64) int64 {
+	return Default.PropertyInt64(name, defaultValue)
+}
+
+// PropertyInt64E int64 value property from the default configuration source provider with the error
+func PropertyInt64E(name string, defaultValue int64) (int64, error) {
+	return Default.PropertyInt64E(name, defaultValue)
+}
+
+// PropertyInt64 int64 value property from the configuration source provider
+func (c *ConfigSourceProvider) PropertyInt64(name string, defaultValue int64) int64 {
+	value, err := c.PropertyInt64E(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyInt64E int64 value property from the configuration source provider with the error
+func (c *ConfigSourceProvider) PropertyInt64E(name string, defaultValue int64) (int64, error) {
+	value, exists, err := c.findProperty(name)
+	if err != nil {
+		return defaultValue, err
+	}
+	if exists {
+		tmp, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return defaultValue, invalidValue(name, err)
+		}
+		return tmp, nil
+	}
+	return defaultValue, nil
+}
+
+// PropertyFloat float value property from the default configuration source provider
+func PropertyFloat(name string, defaultValue float64) float64 {
+	return Default.PropertyFloat(name, defaultValue)
+}
+
+// PropertyFloatE float value property from the default configuration source provider with the error
+func PropertyFloatE(name string, defaultValue float64) (float64, error) {
+	return Default.PropertyFloatE(name, defaultValue)
+}
+
+// PropertyFloat float value property from the configuration source provider
+func (c *ConfigSourceProvider) PropertyFloat(name string, defaultValue float64) float64 {
+	value, err := c.PropertyFloatE(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyFloatE float value property from the configuration source provider with the error
+func (c *ConfigSourceProvider) PropertyFloatE(name string, defaultValue float64) (float64, error) {
+	value, exists, err := c.findProperty(name)
+	if err != nil {
+		return defaultValue, err
+	}
+	if exists {
+		tmp, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return defaultValue, invalidValue(name, err)
+		}
+		return tmp, nil
+	}
+	return defaultValue, nil
+}
+
+// PropertyDuration duration value property from the default configuration source provider
+func PropertyDuration(name string, defaultValue time.Duration) time.Duration {
+	return Default.PropertyDuration(name, defaultValue)
+}
+
+// PropertyDurationE duration value property from the default configuration source provider with the error
+func PropertyDurationE(name string, defaultValue time.Duration) (time.Duration, error) {
+	return Default.PropertyDurationE(name, defaultValue)
+}
+
+// PropertyDuration duration value property from the configuration source provider
+func (c *ConfigSourceProvider) PropertyDuration(name string, defaultValue time.Duration) time.Duration {
+	value, err := c.PropertyDurationE(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyDurationE duration value property from the configuration source provider with the error
+func (c *ConfigSourceProvider) PropertyDurationE(name string, defaultValue time.Duration) (time.Duration, error) {
+	value, exists, err := c.findProperty(name)
+	if err != nil {
+		return defaultValue, err
+	}
+	if exists {
+		tmp, err := time.ParseDuration(value)
+		if err != nil {
+			return defaultValue, invalidValue(name, err)
+		}
+		return tmp, nil
+	}
+	return defaultValue, nil
+}
+
+// PropertyStrings list value property from the default configuration source provider
+func PropertyStrings(name string, defaultValue []string) []string {
+	return Default.PropertyStrings(name, defaultValue)
+}
+
+// PropertyStringsE list value property from the default configuration source provider with the error
+func PropertyStringsE(name string, defaultValue []string) ([]string, error) {
+	return Default.PropertyStringsE(name, defaultValue)
+}
+
+// PropertyStrings list value property from the configuration source provider
+func (c *ConfigSourceProvider) PropertyStrings(name string, defaultValue []string) []string {
+	value, err := c.PropertyStringsE(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyStringsE list value property from the configuration source provider with the error.
+// The list is read from the indexed properties `name[0]`, `name[1]`, ... or from the comma
+// separated value of the property.
+func (c *ConfigSourceProvider) PropertyStringsE(name string, defaultValue []string) ([]string, error) {
+	for _, entry := range c.entries() {
+		if len(c.profile) > 0 {
+			result, exists, err := c.sourceStrings(entry, c.profile+name)
+			if err != nil {
+				return defaultValue, err
+			}
+			if exists {
+				return result, nil
+			}
+		}
+		result, exists, err := c.sourceStrings(entry, name)
+		if err != nil {
+			return defaultValue, err
+		}
+		if exists {
+			return result, nil
+		}
+	}
+	return defaultValue, nil
+}
+
+// sourceStrings read the list from one configuration source, the indexed properties `name[i]`
+// have precedence over the comma separated value so the lists of the sources are never merged
+func (c *ConfigSourceProvider) sourceStrings(entry *sourceEntry, name string) ([]string, bool, error) {
+	result := []string{}
+	for i := 0; ; i++ {
+		value, exists, err := c.sourceProperty(entry, flattenArray(name, i))
+		if err != nil {
+			return nil, false, err
+		}
+		if !exists {
+			break
+		}
+		result = append(result, value)
+	}
+	if len(result) > 0 {
+		return result, true, nil
+	}
+
+	value, exists, err := c.sourceProperty(entry, name)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	return splitList(value), true, nil
+}
+
+// PropertyMap map value property from the default configuration source provider
+func PropertyMap(name string, defaultValue map[string]string) map[string]string {
+	return Default.PropertyMap(name, defaultValue)
+}
+
+// PropertyMapE map value property from the default configuration source provider with the error
+func PropertyMapE(name string, defaultValue map[string]string) (map[string]string, error) {
+	return Default.PropertyMapE(name, defaultValue)
+}
+
+// PropertyMap map value property from the configuration source provider
+func (c *ConfigSourceProvider) PropertyMap(name string, defaultValue map[string]string) map[string]string {
+	value, err := c.PropertyMapE(name, defaultValue)
+	if err != nil {
+		c.logError(name, err)
+		return defaultValue
+	}
+	return value
+}
+
+// PropertyMapE map value property from the configuration source provider with the error.
+// The keys of the map are the property names under the `name.` prefix without the prefix.
+func (c *ConfigSourceProvider) PropertyMapE(name string, defaultValue map[string]string) (map[string]string, error) {
+	keys, err := c.keys(name)
+	if err != nil {
+		return defaultValue, err
+	}
+	if len(keys) == 0 {
+		return defaultValue, nil
+	}
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, exists, err := c.findProperty(flattenName(name, key))
+		if err != nil {
+			return defaultValue, err
+		}
+		if exists {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+// keys sorted property names of all configuration sources under the `name.` prefix without the prefix
+func (c *ConfigSourceProvider) keys(name string) ([]string, error) {
+	entries := c.entries()
+	prefixes := []string{flattenName(name, "")}
+	if len(c.profile) > 0 {
+		prefixes = append(prefixes, c.profile+flattenName(name, ""))
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		properties, err := c.sourceProperties(entry)
+		if err != nil {
+			return nil, err
+		}
+		for key := range properties {
+			for _, prefix := range prefixes {
+				if tmp, ok := c.trimPrefix(key, prefix); ok {
+					if prefix == "" && strings.HasPrefix(tmp, "+") {
+						continue
+					}
+					seen[tmp] = true
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for key := range seen {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// trimPrefix returns the property name without the prefix. The relaxed keys match the prefix by the
+// canonical form and return the rest of the canonical name, the `gluon-log-category-orders-level`
+// flag is `orders.level` under the `gluon.log.category.` prefix.
+func (c *ConfigSourceProvider) trimPrefix(key, prefix string) (string, bool) {
+	if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+		return key[len(prefix):], true
+	}
+	if c.exactKeys || len(prefix) == 0 {
+		return "", false
+	}
+	key = CanonicalKey(key)
+	prefix = CanonicalKey(prefix) + "."
+	if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+		return key[len(prefix):], true
+	}
+	return "", false
+}
+
+// splitList split the comma separated list value
+func splitList(value string) []string {
+	if len(strings.TrimSpace(value)) == 0 {
+		return []string{}
+	}
+	result := strings.Split(value, ",")
+	for i, item := range result {
+		result[i] = strings.TrimSpace(item)
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type PropertyStruct struct {
+	Timeout time.Duration `config:"app.timeout"`
+	Example []string      `config:"app.db.example"`
+	Ports   []int         `config:"app.ports"`
+	Hosts   []string      `config:"app.hosts"`
+}
+
+func TestTypedProperties(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.AddYaml(os.DirFS("tests"))
+	assert.Nil(t, err)
+	err = csp.Add(&EnvConfigSource{Environ: []string{
+		"APP_TIMEOUT=1m30s",
+		"APP_RATIO=0.75",
+		"APP_SIZE=9000000000",
+		"APP_PORTS=80, 443",
+		"APP_INVALID=abc",
+	}})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 90*time.Second, csp.PropertyDuration("app.timeout", time.Second))
+	assert.Equal(t, time.Second, csp.PropertyDuration("app.invalid", time.Second))
+	assert.Equal(t, 0.75, csp.PropertyFloat("app.ratio", 0))
+	assert.Equal(t, int64(9000000000), csp.PropertyInt64("app.size", 0))
+	assert.Equal(t, []string{"asd", "cdf"}, csp.PropertyStrings("app.db.example", nil))
+	assert.Equal(t, []string{"80", "443"}, csp.PropertyStrings("app.ports", nil))
+	assert.Equal(t, []string{"x"}, csp.PropertyStrings("app.none", []string{"x"}))
+
+	_, err = csp.PropertyFloatE("app.invalid", 0)
+	assert.NotNil(t, err)
+	_, err = csp.PropertyInt64E("app.invalid", 0)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, map[string]string{
+		"user":           "test_user",
+		"password":       "test_password",
+		"database":       "test",
+		"example[0]":     "asd",
+		"example[1]":     "cdf",
+		"second[0].data": "123",
+		"second[0].name": "hura",
+		"second[1].data": "789",
+		"second[1].name": "latest",
+	}, csp.PropertyMap("app.db", nil))
+	assert.Nil(t, csp.PropertyMap("app.none", nil))
+
+	csp.SetProfile("dev")
+	assert.Equal(t, "test_user_dev", csp.PropertyMap("app.db", nil)["user"])
+
+	s := &PropertyStruct{Hosts: []string{"localhost"}}
+	err = csp.Properties(s)
+	assert.Nil(t, err)
+	assert.Equal(t, 90*time.Second, s.Timeout)
+	assert.Equal(t, []string{"asd", "cdf"}, s.Example)
+	assert.Equal(t, []int{80, 443}, s.Ports)
+	assert.Equal(t, []string{"localhost"}, s.Hosts)
+}
+
+func TestPropertyStringsError(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&failingConfigSource{fail: true})
+	assert.Nil(t, err)
+
+	result, err := csp.PropertyStringsE("app.hosts", []string{"default"})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"default"}, result)
+
+	csp.SetProfile("dev")
+	result, err = csp.PropertyStringsE("app.hosts", []string{"default"})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"default"}, result)
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-gluon/gluon/log"
+)
+
+// SetRelaxedKeys enable or disable relaxed key matching of the default provider
+func SetRelaxedKeys(enabled bool) {
+	Default.SetRelaxedKeys(enabled)
+}
+
+// SetRelaxedKeys enable or disable relaxed key matching, enabled by default. When the exact
+// property name is not found in the configuration source, the property is matched by the canonical
+// form of the name. `app.db.maxPoolSize`, `app.db.max-pool-size`, `APP_DB_MAX_POOL_SIZE` and
+// `app-db-max-pool-size` address the same property.
+func (c *ConfigSourceProvider) SetRelaxedKeys(enabled bool) {
+	c.exactKeys = !enabled
+}
+
+// VersionedConfigSource configuration source which changes the properties after the initialization.
+// The relaxed key index of the source is rebuilt when the version is changed.
+type VersionedConfigSource interface {
+	ConfigSource
+	// Version of the properties, the version is changed after every change of the properties
+	Version() uint64
+}
+
+// Refresh rebuild the relaxed key index of the configuration sources. The index of the versioned
+// configuration sources and the changes done by the provider are refreshed automatically,
+// Refresh is needed only after the change of other configuration sources.
+func (c *ConfigSourceProvider) Refresh() {
+	for _, entry := range c.entries() {
+		c.reindex(entry)
+	}
+}
+
+// CanonicalKey canonical form of the property name used by the relaxed key matching.
+// The separators `.`, `_`, `-`, `[` and `]` and the camel case humps split the name to the segments,
+// the segments are lower-cased and joined by the dot. `app.db.maxPoolSize` and `APP_DB_MAX_POOL_SIZE`
+// are `app.db.max.pool.size`, `servers[0].host` is `servers.0.host`. The profile prefix `+dev.`
+// (or `_DEV_` for the environment variables) is kept as `+dev.`.
+func CanonicalKey(name string) string {
+	prefix := ""
+	if len(name) > 1 && (name[0] == '+' || name[0] == '_') {
+		end := 1
+		for end < len(name) && isAlphanumeric(name[end]) {
+			end++
+		}
+		if end > 1 {
+			prefix = "+" + strings.ToLower(name[1:end]) + "."
+			name = name[end:]
+		}
+	}
+
+	segments := []string{}
+	for _, item := range strings.FieldsFunc(name, func(r rune) bool {
+		return r < utf8.RuneSelf && !isAlphanumeric(byte(r))
+	}) {
+		for _, word := range splitWords(item) {
+			segments = append(segments, strings.ToLower(word))
+		}
+	}
+	return prefix + strings.Join(segments, ".")
+}
+
+func isAlphanumeric(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// rawProperty get the property from the configuration source by the exact name
+// or by the canonical name of the property
+func (c *ConfigSourceProvider) rawProperty(entry *sourceEntry, name string) (string, bool, error) {
+	value, exists, err := entry.source.Property(name)
+	if err != nil || exists || c.exactKeys {
+		return value, exists, err
+	}
+	if v, ok := entry.source.(VersionedConfigSource); ok && entry.stale(v.Version()) {
+		c.reindex(entry)
+	}
+	raw, found := entry.key(CanonicalKey(name))
+	if !found || raw == name {
+		return value, exists, nil
+	}
+	return entry.source.Property(raw)
+}
+
+// reindex build the relaxed key index of the configuration source
+func (c *ConfigSourceProvider) reindex(entry *sourceEntry) {
+	// the version before the properties, the concurrent change rebuilds the index again
+	var version uint64
+	if v, ok := entry.source.(VersionedConfigSource); ok {
+		version = v.Version()
+	}
+	properties, err := entry.source.Properties()
+	if err != nil {
+		c.logger().Debug("Relaxed key index of the configuration source is not available",
+			log.Err(err).Add("source", entry.source.Name()))
+		properties = map[string]string{}
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	index := make(map[string]string, len(keys))
+	for _, key := range keys {
+		tmp := CanonicalKey(key)
+		if _, exists := index[tmp]; !exists {
+			index[tmp] = key
+		}
+	}
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	entry.index = index
+	entry.version = version
+}
+
+// stale returns true if the relaxed key index was built for the other version of the source
+func (e *sourceEntry) stale(version uint64) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.version != version
+}
+
+// key raw property name of the canonical name
+func (e *sourceEntry) key(canonical string) (string, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	key, exists := e.index[canonical]
+	return key, exists
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalKey(t *testing.T) {
+	assert.Equal(t, "app.db.max.pool.size", CanonicalKey("app.db.maxPoolSize"))
+	assert.Equal(t, "app.db.max.pool.size", CanonicalKey("app.db.max-pool-size"))
+	assert.Equal(t, "app.db.max.pool.size", CanonicalKey("APP_DB_MAX_POOL_SIZE"))
+	assert.Equal(t, "app.db.max.pool.size", CanonicalKey("app-db-max-pool-size"))
+	assert.Equal(t, "app.db.max.pool.size", CanonicalKey("App.DB.MaxPoolSize"))
+	assert.Equal(t, "http.server.port", CanonicalKey("HTTPServer.port"))
+	assert.Equal(t, "servers.0.host", CanonicalKey("servers[0].host"))
+	assert.Equal(t, "servers.0.host", CanonicalKey("SERVERS_0_HOST"))
+	assert.Equal(t, "+dev.app.name", CanonicalKey("+dev.app.name"))
+	assert.Equal(t, "+dev.app.name", CanonicalKey("_DEV_APP_NAME"))
+	assert.NotEqual(t, CanonicalKey("a.bc"), CanonicalKey("ab.c"))
+	assert.NotEqual(t, CanonicalKey("servers[1].0"), CanonicalKey("servers[10]"))
+}
+
+func TestRelaxedKeys(t *testing.T) {
+	for _, item := range []ConfigSource{
+		&EnvConfigSource{Environ: []string{"APP_DB_MAX_POOL_SIZE=10", "SERVERS_0_HOST=a", "SERVERS_1_HOST=b"}},
+		&FlagsConfigSource{Args: []string{"--app-db-max-pool-size=10", "--servers[0]-host=a", "--servers[1]-host=b"}},
+	} {
+		csp := &ConfigSourceProvider{}
+		err := csp.Add(item)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 10, csp.PropertyInt("app.db.maxPoolSize", 0), item.Name())
+		assert.Equal(t, 10, csp.PropertyInt("app.db.max-pool-size", 0), item.Name())
+		assert.Equal(t, 10, csp.PropertyInt("app.db.max_pool_size", 0), item.Name())
+		assert.Equal(t, "a", csp.Property("servers[0].host", "NO_VALUE"), item.Name())
+
+		s := &struct {
+			MaxPoolSize int `config:"app.db.maxPoolSize"`
+		}{}
+		err = csp.Properties(s)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, s.MaxPoolSize, item.Name())
+
+		csp.SetRelaxedKeys(false)
+		assert.Equal(t, 0, csp.PropertyInt("app.db.maxPoolSize", 0), item.Name())
+	}
+}
+
+func TestRelaxedKeysYaml(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: []string{"_DEV_APP_DB_USER=env_dev"}})
+	assert.Nil(t, err)
+	err = csp.AddYaml(os.DirFS("tests"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "test_user", csp.Property("APP_DB_USER", "NO_VALUE"))
+	assert.Equal(t, "test_user", csp.Property("app-db-user", "NO_VALUE"))
+	csp.SetProfile("dev")
+	assert.Equal(t, "env_dev", csp.Property("app.db.user", "NO_VALUE"))
+	assert.Equal(t, "test1-dev", csp.Property("PROPERTY", "NO_VALUE"))
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DiscriminatorProperty default name of the property which selects the implementation of the
+// interface field, it could be changed by the `discriminator` tag of the field
+var DiscriminatorProperty = "type"
+
+// Implementation of the configuration interface registered under the discriminator value
+type Implementation struct {
+	// Interface pointer to the interface type, for example `(*Cache)(nil)`
+	Interface interface{}
+	// Name discriminator value of the implementation
+	Name string
+	// New creates the pointer to the implementation structure
+	New func() interface{}
+}
+
+// RegisterType register implementation of the interface to the default provider
+func RegisterType(iface interface{}, name string, factory func() interface{}) error {
+	return Default.RegisterType(iface, name, factory)
+}
+
+// RegisterType register implementation of the interface under the discriminator value.
+// The interface fields of the configuration structures are bound to the implementation
+// selected by the `<field>.type` property.
+func (c *ConfigSourceProvider) RegisterType(iface interface{}, name string, factory func() interface{}) error {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return errors.New("Configuration type is not pointer to interface")
+	}
+	if factory == nil {
+		return errors.New("Configuration type factory is not defined: " + name)
+	}
+	if c.types == nil {
+		c.types = map[reflect.Type]map[string]func() interface{}{}
+	}
+	if c.types[t.Elem()] == nil {
+		c.types[t.Elem()] = map[string]func() interface{}{}
+	}
+	c.types[t.Elem()][name] = factory
+	return nil
+}
+
+// RegisterTypes register implementations of the interfaces
+func (c *ConfigSourceProvider) RegisterTypes(items ...Implementation) error {
+	for _, item := range items {
+		err := c.RegisterType(item.Interface, item.Name, item.New)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// implementations registered implementations names of the interface sorted by the name
+func (c *ConfigSourceProvider) implementations(t reflect.Type) []string {
+	result := make([]string, 0, len(c.types[t]))
+	for name := range c.types[t] {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// discriminator full name of the discriminator property of the field
+func discriminator(prop string, f reflect.StructField) string {
+	key, ok := f.Tag.Lookup("discriminator")
+	if !ok || len(key) == 0 {
+		key = DiscriminatorProperty
+	}
+	return flattenName(prop, key)
+}
+
+// bindInterface bind the properties to the implementation selected by the discriminator
+// or to the current value of the interface field
+func (c *ConfigSourceProvider) bindInterface(prop string, f reflect.StructField, field reflect.Value) error {
+	factories, registered := c.types[field.Type()]
+	if registered {
+		key := discriminator(prop, f)
+		name, exists, err := c.findProperty(key)
+		if err != nil {
+			return err
+		}
+		if exists {
+			factory, found := factories[name]
+			if !found {
+				return fmt.Errorf("unknown value %s of the property %s, expected one of: %s",
+					name, key, strings.Join(c.implementations(field.Type()), ", "))
+			}
+			value := reflect.ValueOf(factory())
+			if !value.IsValid() || value.Kind() == reflect.Ptr && value.IsNil() {
+				return fmt.Errorf("implementation %s of the property %s is nil", name, key)
+			}
+			if !value.Type().Implements(field.Type()) {
+				return fmt.Errorf("type %v of the property %s does not implement %v", value.Type(), key, field.Type())
+			}
+			if value.Kind() == reflect.Ptr {
+				err = c.bind(prop, value.Elem())
+				if err != nil {
+					return err
+				}
+			}
+			field.Set(value)
+			return nil
+		}
+	}
+
+	// bind to the current value
+	if !field.IsNil() && field.Elem().Kind() == reflect.Ptr && !field.Elem().IsNil() {
+		return c.bind(prop, field.Elem().Elem())
+	}
+	return nil
+}
+
+// describeInterface add the property descriptions of the discriminator and all implementations
+func (c *ConfigSourceProvider) describeInterface(prop string, f reflect.StructField, field reflect.Value, result *[]PropertyDescription) {
+	names := c.implementations(field.Type())
+	if len(names) == 0 {
+		if !field.IsNil() && field.Elem().Kind() == reflect.Ptr && !field.Elem().IsNil() {
+			c.describe(prop, field.Elem().Elem(), result)
+		}
+		return
+	}
+
+	key := discriminator(prop, f)
+	description := f.Tag.Get("description")
+	if len(description) > 0 {
+		description += " "
+	}
+	*result = append(*result, PropertyDescription{
+		Key:         key,
+		Env:         c.envName(key),
+		Flag:        "--" + FlagName(key),
+		Type:        "string",
+		Description: description + "(" + strings.Join(names, ", ") + ")",
+	})
+	for _, name := range names {
+		value := reflect.ValueOf(c.types[field.Type()][name]())
+		if value.Kind() != reflect.Ptr || value.IsNil() {
+			continue
+		}
+		tmp := []PropertyDescription{}
+		c.describe(prop, value.Elem(), &tmp)
+		for _, item := range tmp {
+			item.Description = strings.TrimSpace("[" + key + "=" + name + "] " + item.Description)
+			*result = append(*result, item)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Cache interface {
+	Kind() string
+}
+
+type MemoryCache struct {
+	Size int `config:"size" description:"Maximum entries"`
+}
+
+func (m *MemoryCache) Kind() string { return "memory" }
+
+type RedisCache struct {
+	Address string `config:"address"`
+}
+
+func (r *RedisCache) Kind() string { return "redis" }
+
+func (r *RedisCache) Validate() error {
+	if len(r.Address) == 0 {
+		return errors.New("address is required")
+	}
+	return nil
+}
+
+type StorageConfig struct {
+	Cache    Cache `config:"cache"`
+	Notifier Cache `config:"notifier" discriminator:"kind"`
+	Custom   interface{}
+}
+
+func typesProvider(t *testing.T, environ ...string) *ConfigSourceProvider {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: environ})
+	assert.Nil(t, err)
+	err = csp.RegisterTypes(
+		Implementation{Interface: (*Cache)(nil), Name: "memory", New: func() interface{} { return &MemoryCache{Size: 10} }},
+		Implementation{Interface: (*Cache)(nil), Name: "redis", New: func() interface{} { return &RedisCache{} }},
+	)
+	assert.Nil(t, err)
+	return csp
+}
+
+func TestPolymorphicTypes(t *testing.T) {
+	csp := typesProvider(t,
+		"GLUON_STORAGE_CACHE_TYPE=redis",
+		"GLUON_STORAGE_CACHE_ADDRESS=localhost:6379",
+		"GLUON_STORAGE_NOTIFIER_KIND=memory",
+		"GLUON_STORAGE_NOTIFIER_SIZE=20",
+		"GLUON_STORAGE_CUSTOM_NAME=custom",
+	)
+	s := &StorageConfig{Custom: &Config{}}
+	err := csp.Extension("storage", s)
+	assert.Nil(t, err)
+	assert.Equal(t, &RedisCache{Address: "localhost:6379"}, s.Cache)
+	assert.Equal(t, &MemoryCache{Size: 20}, s.Notifier)
+	assert.Equal(t, &Config{Name: "custom"}, s.Custom)
+
+	csp = typesProvider(t, "GLUON_STORAGE_CACHE_TYPE=redis")
+	s = &StorageConfig{}
+	err = csp.Extension("storage", s)
+	errs := ValidationErrors{}
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, "Cache", errs[0].Field)
+
+	csp = typesProvider(t, "GLUON_STORAGE_CACHE_TYPE=file")
+	err = csp.Extension("storage", &StorageConfig{})
+	assert.EqualError(t, err, "unknown value file of the property gluon.storage.cache.type, expected one of: memory, redis")
+
+	err = csp.RegisterType(Cache(nil), "none", func() interface{} { return nil })
+	assert.NotNil(t, err)
+
+	csp = typesProvider(t, "GLUON_STORAGE_CACHE_TYPE=none")
+	err = csp.RegisterType((*Cache)(nil), "none", func() interface{} { return nil })
+	assert.Nil(t, err)
+	err = csp.Extension("storage", &StorageConfig{})
+	assert.EqualError(t, err, "implementation none of the property gluon.storage.cache.type is nil")
+
+	err = csp.RegisterType((*Cache)(nil), "none", func() interface{} { return (*MemoryCache)(nil) })
+	assert.Nil(t, err)
+	err = csp.Extension("storage", &StorageConfig{})
+	assert.EqualError(t, err, "implementation none of the property gluon.storage.cache.type is nil")
+	_, err = csp.DescribeExtension("storage", &StorageConfig{})
+	assert.Nil(t, err)
+}
+
+func TestDescribeTypes(t *testing.T) {
+	csp := typesProvider(t)
+	items, err := csp.DescribeExtension("storage", &StorageConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, PropertyDescription{
+		Key: "gluon.storage.cache.type", Env: "GLUON_STORAGE_CACHE_TYPE", Flag: "--gluon-storage-cache-type",
+		Type: "string", Description: "(memory, redis)",
+	}, items[0])
+	assert.Equal(t, "gluon.storage.cache.size", items[1].Key)
+	assert.Equal(t, "10", items[1].Default)
+	assert.Equal(t, "[gluon.storage.cache.type=memory] Maximum entries", items[1].Description)
+	assert.Equal(t, "gluon.storage.cache.address", items[2].Key)
+	assert.Equal(t, "gluon.storage.notifier.kind", items[3].Key)
+}
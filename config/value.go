@@ -0,0 +1,122 @@
+package config
+
+import (
+	"time"
+)
+
+// PropertyValue handle of the configuration property. The value is resolved lazily
+// against the current state of the configuration source provider on every call.
+type PropertyValue struct {
+	provider *ConfigSourceProvider
+	name     string
+}
+
+// Value handle of the property from the default configuration source provider
+func Value(name string) *PropertyValue {
+	return Default.Value(name)
+}
+
+// Value handle of the property from the configuration source provider
+func (c *ConfigSourceProvider) Value(name string) *PropertyValue {
+	return &PropertyValue{provider: c, name: name}
+}
+
+// Name of the property
+func (v *PropertyValue) Name() string {
+	return v.name
+}
+
+// Exists returns true if any configuration source provides the property
+func (v *PropertyValue) Exists() bool {
+	_, _, exists, err := v.provider.lookup(v.name)
+	return err == nil && exists
+}
+
+// Source name of the configuration source which provides the property value, empty if none
+func (v *PropertyValue) Source() string {
+	_, source, exists, err := v.provider.lookup(v.name)
+	if err != nil || !exists {
+		return ""
+	}
+	return source.Name()
+}
+
+// String string value of the property
+func (v *PropertyValue) String(defaultValue string) string {
+	return v.provider.Property(v.name, defaultValue)
+}
+
+// StringE string value of the property with the error
+func (v *PropertyValue) StringE(defaultValue string) (string, error) {
+	return v.provider.PropertyE(v.name, defaultValue)
+}
+
+// Bool bool value of the property
+func (v *PropertyValue) Bool(defaultValue bool) bool {
+	return v.provider.PropertyBool(v.name, defaultValue)
+}
+
+// BoolE bool value of the property with the error
+func (v *PropertyValue) BoolE(defaultValue bool) (bool, error) {
+	return v.provider.PropertyBoolE(v.name, defaultValue)
+}
+
+// Int int value of the property
+func (v *PropertyValue) Int(defaultValue int) int {
+	return v.provider.PropertyInt(v.name, defaultValue)
+}
+
+// IntE int value of the property with the error
+func (v *PropertyValue) IntE(defaultValue int) (int, error) {
+	return v.provider.PropertyIntE(v.name, defaultValue)
+}
+
+// Int64 int64 value of the property
+func (v *PropertyValue) Int64(defaultValue int64) int64 {
+	return v.provider.PropertyInt64(v.name, defaultValue)
+}
+
+// Int64E int64 value of the property with the error
+func (v *PropertyValue) Int64E(defaultValue int64) (int64, error) {
+	return v.provider.PropertyInt64E(v.name, defaultValue)
+}
+
+// Float float value of the property
+func (v *PropertyValue) Float(defaultValue float64) float64 {
+	return v.provider.PropertyFloat(v.name, defaultValue)
+}
+
+// FloatE float value of the property with the error
+func (v *PropertyValue) FloatE(defaultValue float64) (float64, error) {
+	return v.provider.PropertyFloatE(v.name, defaultValue)
+}
+
+// Duration duration value of the property
+func (v *PropertyValue) Duration(defaultValue time.Duration) time.Duration {
+	return v.provider.PropertyDuration(v.name, defaultValue)
+}
+
+// DurationE duration value of the property with the error
+func (v *PropertyValue) DurationE(defaultValue time.Duration) (time.Duration, error) {
+	return v.provider.PropertyDurationE(v.name, defaultValue)
+}
+
+// Strings list value of the property
+func (v *PropertyValue) Strings(defaultValue []string) []string {
+	return v.provider.PropertyStrings(v.name, defaultValue)
+}
+
+// StringsE list value of the property with the error
+func (v *PropertyValue) StringsE(defaultValue []string) ([]string, error) {
+	return v.provider.PropertyStringsE(v.name, defaultValue)
+}
+
+// Map map value of the property
+func (v *PropertyValue) Map(defaultValue map[string]string) map[string]string {
+	return v.provider.PropertyMap(v.name, defaultValue)
+}
+
+// MapE map value of the property with the error
+func (v *PropertyValue) MapE(defaultValue map[string]string) (map[string]string, error) {
+	return v.provider.PropertyMapE(v.name, defaultValue)
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.Add(&EnvConfigSource{Environ: []string{"APP_PORT=8080", "APP_TIMEOUT=abc"}})
+	assert.Nil(t, err)
+
+	port := csp.Value("app.port")
+	assert.Equal(t, "app.port", port.Name())
+	assert.True(t, port.Exists())
+	assert.Equal(t, "env", port.Source())
+	assert.Equal(t, 8080, port.Int(0))
+	assert.Equal(t, "8080", port.String(""))
+
+	timeout := csp.Value("app.timeout")
+	assert.Equal(t, time.Second, timeout.Duration(time.Second))
+	_, err = timeout.DurationE(time.Second)
+	assert.NotNil(t, err)
+
+	missing := csp.Value("app.missing")
+	assert.False(t, missing.Exists())
+	assert.Equal(t, "", missing.Source())
+
+	// resolved lazily against the current provider state
+	err = csp.Add(&FlagsConfigSource{Args: []string{"--app-missing=true"}})
+	assert.Nil(t, err)
+	assert.True(t, missing.Exists())
+	assert.Equal(t, "flags", missing.Source())
+	assert.True(t, missing.Bool(false))
+}
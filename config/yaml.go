@@ -1,15 +1,26 @@
 package config
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"io/fs"
+	"path"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
-const resourceFile = "application.yaml"
+const (
+	resourceFile    = "application.yaml"
+	includeProperty = "include"
+)
+
+// YamlProfileProperty property of the yaml document which activates the document only for the profiles
+var YamlProfileProperty = "gluon.config.activate.on-profile"
 
 // AddYaml adds yaml configuration source for the embedded yaml file to the default provider
 func AddYaml(resources fs.FS) error {
@@ -46,17 +57,131 @@ func (y *YamlConfigSource) Init() error {
 		return er
 	}
 
-	d, err := fs.ReadFile(y.resources, rf)
-	if err != nil {
-		return err
+	return y.load(rf, []string{""}, map[string]bool{})
+}
+
+// load the yaml file with all documents and included files. The included files are loaded before
+// the document which includes them and the later documents override the previous ones.
+func (y *YamlConfigSource) load(file string, prefixes []string, visited map[string]bool) error {
+	if visited[file] {
+		return errors.New("Cyclic include of the yaml file: " + file)
 	}
+	visited[file] = true
+	defer delete(visited, file)
 
-	tmp := map[string]interface{}{}
-	err = yaml.Unmarshal(d, &tmp)
+	d, err := fs.ReadFile(y.resources, file)
 	if err != nil {
 		return err
 	}
-	flatten(tmp, "", y.data)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(d))
+	for {
+		doc := map[string]interface{}{}
+		err = decoder.Decode(&doc)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("yaml file %s: %w", file, err)
+		}
+
+		includes := doc[includeProperty]
+		delete(doc, includeProperty)
+
+		tmp := map[string]string{}
+		flatten(doc, "", tmp)
+
+		// document activated only for the profiles
+		docPrefixes := prefixes
+		if profiles, exists := documentProfiles(tmp); exists {
+			docPrefixes = []string{}
+			for _, profile := range profiles {
+				if len(profile) == 0 {
+					continue
+				}
+				// the document included by the profile document is active only for the profiles of both
+				prefix := "+" + profile + "."
+				if containsString(prefixes, "") || containsString(prefixes, prefix) {
+					docPrefixes = append(docPrefixes, prefix)
+				}
+			}
+		}
+
+		for _, include := range includeFiles(includes) {
+			err = y.load(path.Join(path.Dir(file), include), docPrefixes, visited)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, prefix := range docPrefixes {
+			y.clearLists(prefix, tmp)
+			for k, v := range tmp {
+				y.data[prefix+k] = v
+			}
+		}
+	}
+}
+
+// documentProfiles remove the profile property from the document properties and returns the profiles,
+// the property is a comma separated value or a yaml list
+func documentProfiles(properties map[string]string) ([]string, bool) {
+	if value, exists := properties[YamlProfileProperty]; exists {
+		delete(properties, YamlProfileProperty)
+		return splitList(value), true
+	}
+	profiles := []string{}
+	for i := 0; ; i++ {
+		name := flattenArray(YamlProfileProperty, i)
+		value, exists := properties[name]
+		if !exists {
+			break
+		}
+		delete(properties, name)
+		profiles = append(profiles, splitList(value)...)
+	}
+	return profiles, len(profiles) > 0
+}
+
+// clearLists remove the list items of the previous documents overridden by the properties,
+// the shorter list of the later document does not keep the items of the longer list
+func (y *YamlConfigSource) clearLists(prefix string, properties map[string]string) {
+	lists := map[string]bool{}
+	for k := range properties {
+		if i := strings.Index(k, "["); i > 0 {
+			k = k[:i]
+		}
+		lists[prefix+k+"["] = true
+	}
+	for k := range y.data {
+		if i := strings.Index(k, "["); i > 0 && lists[k[:i+1]] {
+			delete(y.data, k)
+		}
+	}
+}
+
+// containsString returns true if the item is in the list
+func containsString(items []string, item string) bool {
+	for _, tmp := range items {
+		if tmp == item {
+			return true
+		}
+	}
+	return false
+}
+
+// includeFiles list of the included files from the `include` value
+func includeFiles(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return splitList(v)
+	case []interface{}:
+		result := []string{}
+		for _, item := range v {
+			result = append(result, toString(item))
+		}
+		return result
+	}
 	return nil
 }
 
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -72,3 +73,79 @@ func TestYamlConfigSource(t *testing.T) {
 	assert.Equal(t, "test_password", input2.App.Db.Password)
 
 }
+
+func TestYamlIncludeAndProfiles(t *testing.T) {
+	csp := &ConfigSourceProvider{}
+	err := csp.AddYaml(os.DirFS("tests/multi"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "multi", csp.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, "app_user", csp.Property("app.db.user", "NO_VALUE"))
+	assert.Equal(t, "db_password", csp.Property("app.db.password", "NO_VALUE"))
+	assert.Equal(t, []string{"orders", "payments"}, csp.PropertyStrings("app.queues", nil))
+	assert.Equal(t, "events", csp.Property("app.topic", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("include", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("app.db.pool", "NO_VALUE"))
+
+	csp.SetProfile("dev")
+	assert.Equal(t, "multi-dev", csp.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, "profile_password", csp.Property("app.db.password", "NO_VALUE"))
+	assert.Equal(t, 5, csp.PropertyInt("app.db.pool", 0))
+
+	csp.SetProfile("test")
+	assert.Equal(t, "multi", csp.Property("app.name", "NO_VALUE"))
+	assert.Equal(t, "profile_password", csp.Property("app.db.password", "NO_VALUE"))
+
+	err = csp.AddYaml(os.DirFS("tests/cycle"))
+	assert.NotNil(t, err)
+}
+
+func TestYamlListOverride(t *testing.T) {
+	resources := fstest.MapFS{
+		"application.yaml": {Data: []byte("include: base.yaml\napp:\n  queues: [orders]\n  servers:\n    - host: a\n---\napp:\n  topics: events\n")},
+		"base.yaml":        {Data: []byte("app:\n  queues: [a, b, c]\n  servers:\n    - host: x\n    - host: y\n  topics: [t1, t2]\n")},
+	}
+	csp := &ConfigSourceProvider{}
+	err := csp.AddYaml(resources)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"orders"}, csp.PropertyStrings("app.queues", nil))
+	assert.Equal(t, "a", csp.Property("app.servers[0].host", "NO_VALUE"))
+	assert.Equal(t, "NO_VALUE", csp.Property("app.servers[1].host", "NO_VALUE"))
+	assert.Equal(t, []string{"events"}, csp.PropertyStrings("app.topics", nil))
+}
+
+func TestYamlNestedProfileInclude(t *testing.T) {
+	resources := fstest.MapFS{
+		"application.yaml": {Data: []byte("gluon.config.activate.on-profile: dev, test\ninclude: profile.yaml\n")},
+		"profile.yaml":     {Data: []byte("app.pool: 5\n---\ngluon.config.activate.on-profile: test, prod\napp.pool: 7\n")},
+	}
+	csp := &ConfigSourceProvider{}
+	err := csp.AddYaml(resources)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, csp.PropertyInt("app.pool", 0))
+
+	csp.SetProfile("dev")
+	assert.Equal(t, 5, csp.PropertyInt("app.pool", 0))
+	csp.SetProfile("test")
+	assert.Equal(t, 7, csp.PropertyInt("app.pool", 0))
+	csp.SetProfile("prod")
+	assert.Equal(t, 0, csp.PropertyInt("app.pool", 0))
+}
+
+func TestYamlProfileList(t *testing.T) {
+	resources := fstest.MapFS{
+		"application.yaml": {Data: []byte("app.pool: 1\n---\ngluon:\n  config:\n    activate:\n      on-profile: [dev, test]\napp.pool: 5\n")},
+	}
+	csp := &ConfigSourceProvider{}
+	err := csp.AddYaml(resources)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, csp.PropertyInt("app.pool", 0))
+	assert.Equal(t, "NO_VALUE", csp.Property("gluon.config.activate.on-profile[0]", "NO_VALUE"))
+
+	csp.SetProfile("dev")
+	assert.Equal(t, 5, csp.PropertyInt("app.pool", 0))
+	csp.SetProfile("test")
+	assert.Equal(t, 5, csp.PropertyInt("app.pool", 0))
+	csp.SetProfile("prod")
+	assert.Equal(t, 1, csp.PropertyInt("app.pool", 0))
+}
@@ -0,0 +1,44 @@
+package gluon_test
+
+import (
+	"embed"
+	"testing"
+
+	"github.com/go-gluon/gluon"
+	"github.com/go-gluon/gluon/config"
+	"github.com/go-gluon/gluon/config/configtest"
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed testdata
+var resources embed.FS
+
+type sampleConfig struct {
+	Name string `config:"name"`
+	Port int    `config:"port"`
+}
+
+type sampleProvider struct {
+	config *sampleConfig
+}
+
+func (p *sampleProvider) NewExtesion() gluon.Extension {
+	return gluon.Extension{
+		Name:   "sample",
+		Config: p.config,
+		Init: func(resources embed.FS, config interface{}) error {
+			return nil
+		},
+	}
+}
+
+func TestConfigtestRegisterExtensions(t *testing.T) {
+	c := configtest.NewProvider(t)
+	configtest.OverrideIn(t, c, "gluon.sample.port", "9090")
+	p := &sampleProvider{config: &sampleConfig{}}
+	configtest.RegisterExtensions(t, c, resources, p)
+
+	assert.Equal(t, "from-yaml", p.config.Name)
+	assert.Equal(t, 9090, p.config.Port)
+	assert.Equal(t, "NO_VALUE", config.Property("gluon.sample.name", "NO_VALUE"))
+}
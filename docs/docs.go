@@ -0,0 +1,186 @@
+package docs
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-gluon/gluon"
+	"github.com/go-gluon/gluon/config"
+)
+
+// Format output format of the documentation
+type Format string
+
+const (
+	// Markdown table output format
+	Markdown Format = "markdown"
+	// HTML table output format
+	HTML Format = "html"
+)
+
+var columns = []string{"Key", "Environment variable", "Flag", "Type", "Default", "Description"}
+
+// defaultColumn index of the default value column, the default value is written as code
+// because it may contain the markdown or HTML markup
+const defaultColumn = 4
+
+// Describe describes the configuration properties of all extensions sorted by the extension priority
+// with a new configuration source provider, the default provider is not changed
+func Describe(providers ...gluon.ExtensionProvider) ([]config.PropertyDescription, error) {
+	return DescribeWith(&config.ConfigSourceProvider{}, providers...)
+}
+
+// DescribeWith describes the configuration properties of all extensions sorted by the extension priority
+// with the naming strategy and the registered types of the configuration source provider
+func DescribeWith(c *config.ConfigSourceProvider, providers ...gluon.ExtensionProvider) ([]config.PropertyDescription, error) {
+	extensions := make([]gluon.Extension, len(providers))
+	for i, p := range providers {
+		extensions[i] = p.NewExtesion()
+	}
+	sort.SliceStable(extensions, func(i, j int) bool {
+		return extensions[i].Priority < extensions[j].Priority
+	})
+
+	for _, e := range extensions {
+		err := c.RegisterTypes(e.Types...)
+		if err != nil {
+			return nil, fmt.Errorf("extension %s: %w", e.Name, err)
+		}
+	}
+
+	result := []config.PropertyDescription{}
+	for _, e := range extensions {
+		if e.Config == nil {
+			continue
+		}
+		tmp, err := c.DescribeExtension(e.Name, e.Config)
+		if err != nil {
+			return nil, fmt.Errorf("extension %s: %w", e.Name, err)
+		}
+		result = append(result, tmp...)
+	}
+	return result, nil
+}
+
+// Generate writes the configuration reference documentation of all extensions
+func Generate(w io.Writer, format Format, providers ...gluon.ExtensionProvider) error {
+	items, err := Describe(providers...)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case Markdown:
+		return writeMarkdown(w, items)
+	case HTML:
+		return writeHTML(w, items)
+	}
+	return errors.New("Not supported documentation format: " + string(format))
+}
+
+// Main entry point for the `go run` documentation generator
+//
+//	func main() {
+//		docs.Main(&myext.Provider{}, &otherext.Provider{})
+//	}
+func Main(providers ...gluon.ExtensionProvider) {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	format := fs.String("format", string(Markdown), "output format (markdown, html)")
+	output := fs.String("output", "", "output file (default stdout)")
+	_ = fs.Parse(os.Args[1:])
+
+	var file *os.File
+	var w io.Writer = os.Stdout
+	if len(*output) > 0 {
+		var err error
+		file, err = os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		w = file
+	}
+
+	err := Generate(w, Format(*format), providers...)
+	if file != nil {
+		// os.Exit skips the deferred functions, close the file before the exit
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func row(p config.PropertyDescription) []string {
+	flag := p.Flag
+	if len(p.Short) > 0 {
+		flag = "-" + p.Short + ", " + flag
+	}
+	return []string{p.Key, p.Env, flag, p.Type, p.Default, p.Description}
+}
+
+func writeMarkdown(w io.Writer, items []config.PropertyDescription) error {
+	sep := make([]string, len(columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	lines := []string{markdownRow(columns), markdownRow(sep)}
+	for _, item := range items {
+		tmp := row(item)
+		for i, v := range tmp {
+			v = strings.ReplaceAll(v, "|", "\\|")
+			v = strings.ReplaceAll(v, "\n", " ")
+			if (i < 3 || i == defaultColumn) && len(v) > 0 {
+				v = markdownCode(v)
+			}
+			tmp[i] = v
+		}
+		lines = append(lines, markdownRow(tmp))
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// markdownCode wraps the value in the code span, the value with the backtick is wrapped in the double backticks
+func markdownCode(v string) string {
+	if strings.Contains(v, "`") {
+		return "`` " + v + " ``"
+	}
+	return "`" + v + "`"
+}
+
+func markdownRow(items []string) string {
+	return "| " + strings.Join(items, " | ") + " |"
+}
+
+func writeHTML(w io.Writer, items []config.PropertyDescription) error {
+	b := &strings.Builder{}
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for _, c := range columns {
+		b.WriteString("<th>" + html.EscapeString(c) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, item := range items {
+		b.WriteString("<tr>")
+		for i, v := range row(item) {
+			v = html.EscapeString(v)
+			if (i < 3 || i == defaultColumn) && len(v) > 0 {
+				v = "<code>" + v + "</code>"
+			}
+			b.WriteString("<td>" + v + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,71 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gluon/gluon"
+	"github.com/go-gluon/gluon/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	Host string `config:"host" description:"Server | host"`
+	Port int    `config:"port"`
+}
+
+type testProvider struct{}
+
+func (p testProvider) NewExtesion() gluon.Extension {
+	return gluon.Extension{Name: "server", Config: &testConfig{Host: "<localhost>", Port: 80}}
+}
+
+type emptyProvider struct{}
+
+func (p emptyProvider) NewExtesion() gluon.Extension {
+	return gluon.Extension{Name: "empty"}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	b := &strings.Builder{}
+	err := Generate(b, Markdown, testProvider{}, emptyProvider{})
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, "| `gluon.server.host` | `GLUON_SERVER_HOST` | `--gluon-server-host` | string | `<localhost>` | Server \\| host |", lines[2])
+	assert.Equal(t, "| `gluon.server.port` | `GLUON_SERVER_PORT` | `--gluon-server-port` | int | `80` |  |", lines[3])
+}
+
+func TestMarkdownCode(t *testing.T) {
+	assert.Equal(t, "`*`", markdownCode("*"))
+	assert.Equal(t, "`` a`b ``", markdownCode("a`b"))
+}
+
+func TestGenerateHTML(t *testing.T) {
+	b := &strings.Builder{}
+	err := Generate(b, HTML, testProvider{})
+	assert.Nil(t, err)
+	assert.Contains(t, b.String(), "<td><code>gluon.server.host</code></td>")
+	assert.Contains(t, b.String(), "<td><code>&lt;localhost&gt;</code></td>")
+
+	err = Generate(b, Format("pdf"), testProvider{})
+	assert.NotNil(t, err)
+}
+
+type namingConfig struct {
+	MaxPoolSize int
+}
+
+type namingProvider struct{}
+
+func (p namingProvider) NewExtesion() gluon.Extension {
+	return gluon.Extension{Name: "db", Config: &namingConfig{MaxPoolSize: 10}}
+}
+
+func TestDescribeWith(t *testing.T) {
+	c := &config.ConfigSourceProvider{}
+	c.SetNamingStrategy(config.KebabCase)
+	items, err := DescribeWith(c, namingProvider{})
+	assert.Nil(t, err)
+	assert.Equal(t, "gluon.db.max-pool-size", items[0].Key)
+}
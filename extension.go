@@ -2,7 +2,6 @@ package gluon
 
 import (
 	"embed"
-	"sort"
 
 	"github.com/go-gluon/gluon/config"
 	"github.com/go-gluon/gluon/log"
@@ -10,51 +9,47 @@ import (
 
 type ExtensionInit = func(resources embed.FS, config interface{}) error
 
+// ExtensionInitLogger initialize the extension with the logger named after the extension
+type ExtensionInitLogger = func(resources embed.FS, config interface{}, logger log.Logger) error
+
 type Extension struct {
 	Name     string
 	Priority int
 	Init     ExtensionInit
-	Config   interface{}
+	// InitLogger alternative of the Init function which gets the logger named after the extension,
+	// the extension has either the Init or the InitLogger function
+	InitLogger ExtensionInitLogger
+	Config     interface{}
+	// Types implementations of the configuration interfaces selected by the discriminator property
+	Types []config.Implementation
+	// Commands subcommands of the application provided by the extension
+	Commands []Command
 }
 
 type ExtensionProvider interface {
 	NewExtesion() Extension
 }
 
+// RegisterExtensions start a new application with the extensions and the default configuration source provider
 func RegisterExtensions(resources embed.FS, providers ...ExtensionProvider) error {
-	// core modules
-	err := config.AddYaml(resources)
+	return RegisterExtensionsWith(config.Default, resources, providers...)
+}
+
+// RegisterExtensionsWith start a new application with the extensions and the configuration source provider
+func RegisterExtensionsWith(c *config.ConfigSourceProvider, resources embed.FS, providers ...ExtensionProvider) error {
+	app, err := New(WithConfig(c), WithResources(resources), WithExtensions(providers...))
 	if err != nil {
-		panic(err)
+		return err
 	}
+	return app.Start()
+}
 
-	// activate extension
-	if len(providers) > 0 {
-		extensions := make([]Extension, len(providers))
-		for i, e := range providers {
-			extensions[i] = e.NewExtesion()
-		}
-
-		// sort extension base on the priority
-		sort.Slice(extensions, func(i, j int) bool {
-			return extensions[i].Priority < extensions[j].Priority
-		})
-
-		tmp := make([]string, len(extensions))
-		for i, e := range extensions {
-			tmp[i] = e.Name
-
-			err := config.Extension(e.Name, e.Config)
-			if err != nil {
-				return err
-			}
-
-			err = e.Init(resources, e.Config)
-			if err != nil {
-				panic(err)
-			}
-		}
-		log.Info("Loaded extension", log.Fields{"extensions": tmp})
+// Run register extensions with the default configuration source provider and run the command
+// selected by the command line arguments
+func Run(resources embed.FS, providers ...ExtensionProvider) error {
+	app, err := New(WithConfig(config.Default), WithResources(resources), WithExtensions(providers...))
+	if err != nil {
+		return err
 	}
-	return nil
+	return app.Run()
 }
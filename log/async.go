@@ -0,0 +1,229 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy behavior of the asynchronous logger when the queue is full
+type OverflowPolicy int
+
+const (
+	// Block the caller until the queue has space
+	Block OverflowPolicy = iota
+	// DropNewest drop the new entry
+	DropNewest
+	// DropOldest drop the oldest entry in the queue
+	DropOldest
+)
+
+var overflowNames = []string{"block", "drop-newest", "drop-oldest"}
+
+func (p OverflowPolicy) String() string {
+	if p < Block || p > DropOldest {
+		return fmt.Sprintf("overflow(%d)", int(p))
+	}
+	return overflowNames[p]
+}
+
+// ParseOverflowPolicy parse the overflow policy name: block, drop-newest or drop-oldest
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	for i, item := range overflowNames {
+		if item == name {
+			return OverflowPolicy(i), nil
+		}
+	}
+	return Block, fmt.Errorf("unknown overflow policy: %q", name)
+}
+
+// Flusher is implemented by the loggers which buffer the entries
+type Flusher interface {
+	Flush()
+}
+
+// AsyncLogger writes the entries which pass the global level to the target logger in the
+// background goroutine. The entries are buffered in the bounded queue.
+type AsyncLogger struct {
+	target  Logger
+	policy  OverflowPolicy
+	queue   chan *Entry
+	flush   chan chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+	dropped uint64
+	mutex   sync.RWMutex
+	closed  bool
+}
+
+// NewAsyncLogger create asynchronous logger with the queue size and start the writer goroutine
+func NewAsyncLogger(target Logger, size int, policy OverflowPolicy) *AsyncLogger {
+	if size < 1 {
+		size = 1
+	}
+	a := &AsyncLogger{
+		target:  target,
+		policy:  policy,
+		queue:   make(chan *Entry, size),
+		flush:   make(chan chan struct{}),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *AsyncLogger) Trace(msg string, fields ...map[string]interface{}) {
+	a.log(TraceLevel, msg, fields)
+}
+
+func (a *AsyncLogger) Debug(msg string, fields ...map[string]interface{}) {
+	a.log(DebugLevel, msg, fields)
+}
+
+func (a *AsyncLogger) Info(msg string, fields ...map[string]interface{}) {
+	a.log(InfoLevel, msg, fields)
+}
+
+func (a *AsyncLogger) Warn(msg string, fields ...map[string]interface{}) {
+	a.log(WarnLevel, msg, fields)
+}
+
+func (a *AsyncLogger) Error(msg string, fields ...map[string]interface{}) {
+	a.log(ErrorLevel, msg, fields)
+}
+
+func (a *AsyncLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	if IsEnabled(level) {
+		a.WriteEntry(newEntry(level, msg, fields))
+	}
+}
+
+// WriteEntry add the entry to the queue, the entries are written synchronously after Close.
+// The fields are copied, the caller may change the maps after the call.
+func (a *AsyncLogger) WriteEntry(e *Entry) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.closed {
+		write(a.target, e)
+		return
+	}
+	if len(e.Fields) > 0 {
+		e.Fields = []map[string]interface{}{MergeFields(e.Fields...)}
+	}
+
+	switch a.policy {
+	case DropNewest:
+		select {
+		case a.queue <- e:
+		default:
+			atomic.AddUint64(&a.dropped, 1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case a.queue <- e:
+				return
+			default:
+			}
+			select {
+			case <-a.queue:
+				atomic.AddUint64(&a.dropped, 1)
+			default:
+			}
+		}
+	default:
+		a.queue <- e
+	}
+}
+
+// Dropped returns the number of the dropped entries
+func (a *AsyncLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Flush wait until the entries added before the call are written
+func (a *AsyncLogger) Flush() {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.closed {
+		return
+	}
+	done := make(chan struct{})
+	a.flush <- done
+	<-done
+	if f, ok := a.target.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close write the queued entries, stop the writer goroutine and close the target logger
+func (a *AsyncLogger) Close() error {
+	if !a.detach() {
+		return nil
+	}
+	if c, ok := a.target.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// detach write the queued entries and stop the writer goroutine without closing the target logger,
+// returns false if the logger is already closed
+func (a *AsyncLogger) detach() bool {
+	a.mutex.Lock()
+	if a.closed {
+		a.mutex.Unlock()
+		return false
+	}
+	a.closed = true
+	a.mutex.Unlock()
+
+	close(a.stop)
+	<-a.stopped
+	return true
+}
+
+func (a *AsyncLogger) run() {
+	defer close(a.stopped)
+	for {
+		select {
+		case e := <-a.queue:
+			write(a.target, e)
+		case done := <-a.flush:
+			a.drain()
+			close(done)
+		case <-a.stop:
+			a.drain()
+			return
+		}
+	}
+}
+
+// drain write all queued entries
+func (a *AsyncLogger) drain() {
+	for {
+		select {
+		case e := <-a.queue:
+			write(a.target, e)
+		default:
+			return
+		}
+	}
+}
+
+// Flush wait until the global logger writes the buffered entries
+func Flush() {
+	if f, ok := Log.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close write the buffered entries and close the global logger
+func Close() error {
+	if c, ok := Log.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
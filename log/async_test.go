@@ -0,0 +1,127 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingLogger blocks the writing until the channel is closed
+type blockingLogger struct {
+	entries
+	mutex   sync.Mutex
+	release chan struct{}
+	closed  bool
+}
+
+func (l *blockingLogger) WriteEntry(e *Entry) {
+	<-l.release
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries.WriteEntry(e)
+}
+
+func (l *blockingLogger) messages() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	result := []string{}
+	for _, e := range l.items {
+		result = append(result, e.Message)
+	}
+	return result
+}
+
+func (l *blockingLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestAsyncLoggerFlush(t *testing.T) {
+	l := &blockingLogger{release: make(chan struct{})}
+	close(l.release)
+	a := NewAsyncLogger(l, 10, Block)
+	for _, msg := range []string{"a", "b", "c"} {
+		a.Info(msg)
+	}
+	a.Debug("skipped")
+	a.Flush()
+	assert.Equal(t, []string{"a", "b", "c"}, l.messages())
+
+	a.Warn("d")
+	assert.Nil(t, a.Close())
+	assert.True(t, l.closed)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, l.messages())
+
+	a.Error("after close")
+	assert.Equal(t, []string{"a", "b", "c", "d", "after close"}, l.messages())
+	assert.Nil(t, a.Close())
+}
+
+func TestAsyncLoggerOverflow(t *testing.T) {
+	for _, test := range []struct {
+		policy   OverflowPolicy
+		expected []string
+	}{
+		{DropNewest, []string{"first", "1", "2"}},
+		{DropOldest, []string{"first", "3", "4"}},
+	} {
+		l := &blockingLogger{release: make(chan struct{})}
+		a := NewAsyncLogger(l, 2, test.policy)
+		a.Info("first")
+		// wait until the writer goroutine takes the first entry
+		for len(a.queue) > 0 {
+			runtime.Gosched()
+		}
+		for _, msg := range []string{"1", "2", "3", "4"} {
+			a.Info(msg)
+		}
+		assert.Equal(t, uint64(2), a.Dropped())
+		close(l.release)
+		assert.Nil(t, a.Close())
+		assert.Equal(t, test.expected, l.messages(), test.policy.String())
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	p, err := ParseOverflowPolicy("drop-oldest")
+	assert.Nil(t, err)
+	assert.Equal(t, DropOldest, p)
+	_, err = ParseOverflowPolicy("drop")
+	assert.EqualError(t, err, `unknown overflow policy: "drop"`)
+}
+
+func TestConfigureAsync(t *testing.T) {
+	original := Log
+	l := &entries{}
+	Log = l
+	t.Cleanup(func() {
+		Log = original
+	})
+	cfg := NewConfig()
+	cfg.Async.Enabled = true
+	cfg.Async.Overflow = "drop-newest"
+	assert.Nil(t, Configure(cfg))
+	assert.IsType(t, &AsyncLogger{}, Log)
+
+	fields := Fields{"id": 1}
+	Info("queued", fields)
+	fields["id"] = 2
+	Flush()
+	assert.Len(t, l.items, 1)
+	assert.Equal(t, Fields{"id": 1}, MergeFields(l.items[0].Fields...))
+
+	// the changed queue size replaces the asynchronous logger
+	a := Log.(*AsyncLogger)
+	cfg.Async.QueueSize = 10
+	assert.Nil(t, Configure(cfg))
+	assert.NotSame(t, a, Log)
+	assert.Equal(t, 10, cap(Log.(*AsyncLogger).queue))
+	a.Info("written")
+	assert.Len(t, l.items, 2)
+	assert.Nil(t, Close())
+
+	cfg.Async.Overflow = "drop"
+	assert.EqualError(t, Configure(cfg), `unknown overflow policy: "drop"`)
+}
@@ -0,0 +1,149 @@
+package log
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Caller location of the log call
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// String returns the file with the parent directory and the line, for example `log/caller.go:12`
+func (c Caller) String() string {
+	file := c.File
+	if i := strings.LastIndex(file, "/"); i >= 0 {
+		if j := strings.LastIndex(file[:i], "/"); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return file + ":" + strconv.Itoa(c.Line)
+}
+
+var (
+	callerEnabled int32
+	stackLevel    = int32(OffLevel)
+	logDir        = packageDir()
+)
+
+// SetCaller enable adding of the caller location to the entries
+func SetCaller(enabled bool) {
+	value := int32(0)
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&callerEnabled, value)
+}
+
+// SetStackLevel set the minimum level of the entries with the stack trace, default OffLevel
+func SetStackLevel(l Level) {
+	atomic.StoreInt32(&stackLevel, int32(l))
+}
+
+func packageDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return path.Dir(file)
+}
+
+// isLogFrame returns true for the frames of this package except the tests
+func isLogFrame(file string) bool {
+	return path.Dir(file) == logDir && !strings.HasSuffix(file, "_test.go")
+}
+
+// capture add the caller and the stack trace to the entry, the frames of this package are skipped
+// so the location is the same for the package functions and the logger methods
+func capture(e *Entry) {
+	withCaller := atomic.LoadInt32(&callerEnabled) == 1
+	withStack := e.Level >= Level(atomic.LoadInt32(&stackLevel))
+	if !withCaller && !withStack {
+		return
+	}
+
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := strings.Builder{}
+	found := false
+	for {
+		frame, more := frames.Next()
+		if found || !isLogFrame(frame.File) {
+			if !found && withCaller {
+				e.Caller = &Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+			}
+			found = true
+			if !withStack {
+				break
+			}
+			stack.WriteString(frame.Function)
+			stack.WriteString("\n\t")
+			stack.WriteString(frame.File)
+			stack.WriteString(":")
+			stack.WriteString(strconv.Itoa(frame.Line))
+			stack.WriteString("\n")
+		}
+		if !more {
+			break
+		}
+	}
+	e.Stack = stack.String()
+}
+
+// errorChain returns the messages of the error and all wrapped errors or nil for the error
+// which does not wrap any error. The errors joined by the `Unwrap() []error` method are
+// visited depth-first and the nil pointer errors are skipped.
+func errorChain(err error) []string {
+	if isNil(err) || len(unwrap(err)) == 0 {
+		return nil
+	}
+	result := []string{}
+	var visit func(err error)
+	visit = func(err error) {
+		if isNil(err) {
+			return
+		}
+		result = append(result, err.Error())
+		for _, item := range unwrap(err) {
+			visit(item)
+		}
+	}
+	visit(err)
+	return result
+}
+
+// unwrap returns the errors wrapped by the `Unwrap() error` or the `Unwrap() []error` method
+func unwrap(err error) []error {
+	switch tmp := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := tmp.Unwrap(); inner != nil {
+			return []error{inner}
+		}
+	case interface{ Unwrap() []error }:
+		return tmp.Unwrap()
+	}
+	return nil
+}
+
+// entryFields merge the fields of the entry and add the `<name>.chain` fields of the wrapped errors
+func entryFields(e *Entry) Fields {
+	fields := MergeFields(e.Fields...)
+	chains := Fields{}
+	for k, v := range fields {
+		if err, ok := v.(error); ok {
+			if chain := errorChain(err); chain != nil {
+				chains[k+".chain"] = chain
+			}
+		}
+	}
+	for k, v := range chains {
+		if _, exists := fields[k]; !exists {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,110 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type captureFormatter struct {
+	items []*Entry
+}
+
+func (c *captureFormatter) Format(e *Entry) []byte {
+	c.items = append(c.items, e)
+	return nil
+}
+
+func line() int {
+	_, _, l, _ := runtime.Caller(1)
+	return l
+}
+
+func TestCaller(t *testing.T) {
+	original := Log
+	f := &captureFormatter{}
+	Log = NewFormatLogger(&bytes.Buffer{}, f)
+	t.Cleanup(func() {
+		Log = original
+		SetCaller(false)
+		SetStackLevel(OffLevel)
+	})
+	SetCaller(true)
+	SetStackLevel(ErrorLevel)
+
+	l := line() + 1
+	Info("package")
+	ErrorE("error", errors.New("failed"))
+	Log.Warn("method")
+	Named("orders").With(Add("id", 1)).Info("child")
+
+	assert.Len(t, f.items, 4)
+	for i, e := range f.items {
+		assert.Equal(t, fmt.Sprintf("log/caller_test.go:%d", l+i), e.Caller.String())
+		assert.Equal(t, "github.com/go-gluon/gluon/log.TestCaller", e.Caller.Function)
+	}
+	assert.Empty(t, f.items[0].Stack)
+	assert.Regexp(t, "^github.com/go-gluon/gluon/log.TestCaller\n\t.*/log/caller_test.go:\\d+\n", f.items[1].Stack)
+}
+
+func TestErrorChain(t *testing.T) {
+	root := errors.New("connection refused")
+	err := fmt.Errorf("load order: %w", fmt.Errorf("query: %w", root))
+	e := &Entry{Level: ErrorLevel, Message: "failed", Fields: []map[string]interface{}{Err(err).Add("cause", root)}}
+	assert.Equal(t, Fields{
+		"error":       err,
+		"error.chain": []string{"load order: query: connection refused", "query: connection refused", "connection refused"},
+		"cause":       root,
+	}, entryFields(e))
+	assert.Contains(t, string(JSONFormatter{}.Format(e)),
+		`"error.chain":["load order: query: connection refused","query: connection refused","connection refused"]`)
+}
+
+type joinedError []error
+
+func (j joinedError) Error() string {
+	return "joined"
+}
+
+func (j joinedError) Unwrap() []error {
+	return j
+}
+
+type nilError struct{}
+
+func (*nilError) Error() string {
+	return "nil"
+}
+
+func TestErrorChainJoined(t *testing.T) {
+	var missing *nilError
+	err := fmt.Errorf("save: %w", joinedError{errors.New("a"), fmt.Errorf("b: %w", errors.New("c")), missing})
+	assert.Equal(t, []string{"save: joined", "joined", "a", "b: c", "c"}, errorChain(err))
+	assert.Nil(t, errorChain(missing))
+	assert.Nil(t, errorChain(errors.New("plain")))
+
+	e := &Entry{Level: ErrorLevel, Message: "failed", Fields: []map[string]interface{}{Err(fmt.Errorf("load: %w", errors.New("eof")))}}
+	assert.Equal(t, "[ERROR] failed [map[error:load: eof error.chain:[load: eof eof]]]\n", string(TextFormatter{}.Format(e)))
+}
+
+type fieldsLogger struct {
+	fields []Fields
+}
+
+func (l *fieldsLogger) Trace(msg string, fields ...map[string]interface{}) {}
+func (l *fieldsLogger) Debug(msg string, fields ...map[string]interface{}) {}
+func (l *fieldsLogger) Info(msg string, fields ...map[string]interface{})  {}
+func (l *fieldsLogger) Warn(msg string, fields ...map[string]interface{})  {}
+func (l *fieldsLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.fields = append(l.fields, MergeFields(fields...))
+}
+
+func TestWriteCallerFields(t *testing.T) {
+	l := &fieldsLogger{}
+	write(l, &Entry{Level: ErrorLevel, Message: "failed", Category: "orders", Caller: &Caller{File: "/src/app/main.go", Line: 10, Function: "main.main"}, Stack: "main.main\n"})
+	assert.Equal(t, []Fields{{"category": "orders", "caller": "app/main.go:10", "function": "main.main", "stack": "main.main\n"}}, l.fields)
+}
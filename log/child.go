@@ -0,0 +1,108 @@
+package log
+
+// Child logger which attaches the name and the fields to every entry. The named loggers check
+// the level of the category with the same name, the name is the category of the entries.
+// The category levels are the levels of the parent LevelLogger or the global levels.
+type Child struct {
+	parent Logger
+	name   string
+	fields Fields
+}
+
+// From returns the child logger of the parent logger, nil parent means the global logger Log
+func From(parent Logger) *Child {
+	if c, ok := parent.(*Child); ok {
+		return c
+	}
+	return &Child{parent: parent}
+}
+
+// With returns the child logger of the global logger with the fields
+func With(fields map[string]interface{}) *Child {
+	return From(nil).With(fields)
+}
+
+// Named returns the child logger of the global logger with the name
+func Named(name string) *Child {
+	return From(nil).Named(name)
+}
+
+// Category returns the logger which checks the level of the category instead of the global level
+func Category(name string) Logger {
+	return Named(name)
+}
+
+// With returns the child logger with the fields added to the fields of the logger
+func (c *Child) With(fields map[string]interface{}) *Child {
+	tmp := make(Fields, len(c.fields)+len(fields))
+	for k, v := range c.fields {
+		tmp[k] = v
+	}
+	for k, v := range fields {
+		tmp[k] = v
+	}
+	return &Child{parent: c.parent, name: c.name, fields: tmp}
+}
+
+// Named returns the child logger with the name appended to the name of the logger with the dot,
+// for example `orders` and `db` is `orders.db`
+func (c *Child) Named(name string) *Child {
+	if len(c.name) > 0 && len(name) > 0 {
+		name = c.name + "." + name
+	} else if len(name) == 0 {
+		name = c.name
+	}
+	return &Child{parent: c.parent, name: name, fields: c.fields}
+}
+
+// Name of the logger
+func (c *Child) Name() string {
+	return c.name
+}
+
+func (c *Child) Trace(msg string, fields ...map[string]interface{}) {
+	c.log(TraceLevel, msg, fields)
+}
+
+func (c *Child) Debug(msg string, fields ...map[string]interface{}) {
+	c.log(DebugLevel, msg, fields)
+}
+
+func (c *Child) Info(msg string, fields ...map[string]interface{}) {
+	c.log(InfoLevel, msg, fields)
+}
+
+func (c *Child) Warn(msg string, fields ...map[string]interface{}) {
+	c.log(WarnLevel, msg, fields)
+}
+
+func (c *Child) Error(msg string, fields ...map[string]interface{}) {
+	c.log(ErrorLevel, msg, fields)
+}
+
+// IsEnabled returns true if the level passes the minimum level of the category of the logger
+func (c *Child) IsEnabled(level Level) bool {
+	return levelsOf(c.parent).IsCategoryEnabled(c.name, level)
+}
+
+func (c *Child) log(level Level, msg string, fields []map[string]interface{}) {
+	if !c.IsEnabled(level) {
+		return
+	}
+	c.WriteEntry(newEntry(level, msg, fields))
+}
+
+// WriteEntry writes the entry with the name and the fields of the logger to the parent logger
+func (c *Child) WriteEntry(e *Entry) {
+	if len(c.fields) > 0 {
+		e.Fields = append([]map[string]interface{}{c.fields}, e.Fields...)
+	}
+	if len(e.Category) == 0 {
+		e.Category = c.name
+	}
+	parent := c.parent
+	if parent == nil {
+		parent = Log
+	}
+	write(parent, e)
+}
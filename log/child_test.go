@@ -0,0 +1,34 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChildLogger(t *testing.T) {
+	original := Log
+	l := &entries{}
+	Log = l
+	t.Cleanup(func() {
+		Log = original
+		RemoveCategoryLevel("orders.db")
+	})
+	SetCategoryLevel("orders.db", DebugLevel)
+
+	orders := Named("orders").With(Fields{"extension": "orders", "tenant": "a"})
+	db := orders.Named("db").With(Fields{"tenant": "b"})
+	orders.Debug("skipped")
+	db.Debug("query", Add("request_id", "r1"))
+	With(Add("request_id", "r2")).Info("received")
+
+	assert.Len(t, l.items, 2)
+	assert.Equal(t, "orders.db", l.items[0].Category)
+	assert.Equal(t, Fields{"extension": "orders", "tenant": "b", "request_id": "r1"}, MergeFields(l.items[0].Fields...))
+	assert.Equal(t, "", l.items[1].Category)
+	assert.Equal(t, Fields{"request_id": "r2"}, MergeFields(l.items[1].Fields...))
+	assert.Equal(t, "orders", orders.Name())
+
+	// the child does not change the fields of the parent
+	assert.Equal(t, Fields{"extension": "orders", "tenant": "a"}, orders.fields)
+}
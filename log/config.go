@@ -0,0 +1,360 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// Config logging configuration bound from the `gluon.log` properties
+type Config struct {
+	// Level global minimum level
+	Level string `config:"level" description:"Minimum level of the log entries: trace, debug, info, warn, error or off"`
+	// Format of the log entries, empty keeps the current logger
+	Format string `config:"format" description:"Format of the log entries: text, json, logfmt or console, empty keeps the current logger"`
+	// Outputs names of the outputs configured by the `gluon.log.output.<name>` properties
+	Outputs []string `config:"outputs" description:"Names of the log outputs configured by the gluon.log.output.<name> properties, empty writes to the standard output"`
+	// Output configuration of the outputs by the name
+	Output map[string]OutputConfig `config:"-"`
+	// Caller add the caller location to the entries
+	Caller bool `config:"caller" description:"Add the caller file, line and function to the log entries"`
+	// StackLevel minimum level of the entries with the stack trace
+	StackLevel string `config:"stack-level" description:"Minimum level of the log entries with the stack trace, off disables the stack traces"`
+	// Async asynchronous writing of the entries
+	Async AsyncConfig `config:"async"`
+	// Sampling of the entries with the same level and message
+	Sampling SamplingConfig `config:"sampling"`
+	// Categories minimum levels of the categories from the `gluon.log.category."<name>".level` properties
+	Categories map[string]string `config:"-"`
+}
+
+// OutputConfig configuration of the log output bound from the `gluon.log.output.<name>` properties
+type OutputConfig struct {
+	// Type of the output: stdout, stderr or file, default the name of the output
+	Type string `config:"type" description:"Type of the output: stdout, stderr or file, default the name of the output"`
+	// Path of the file
+	Path string `config:"path" description:"Path of the log file"`
+	// Level minimum level of the output
+	Level string `config:"level" description:"Minimum level of the output"`
+	// Format of the output, default the global format or text
+	Format string `config:"format" description:"Format of the output, default the global format"`
+	// MaxSize maximum size of the file in bytes
+	MaxSize int64 `config:"max-size" description:"Maximum size of the log file in bytes before the rotation"`
+	// Interval of the file rotation
+	Interval time.Duration `config:"interval" description:"Interval of the log file rotation"`
+	// MaxBackups number of the kept backups
+	MaxBackups int `config:"max-backups" description:"Number of the kept log file backups, zero keeps all"`
+	// Compress the backups with gzip
+	Compress bool `config:"compress" description:"Compress the log file backups with gzip"`
+}
+
+// AsyncConfig configuration of the asynchronous logger bound from the `gluon.log.async` properties
+type AsyncConfig struct {
+	// Enabled write the entries in the background goroutine
+	Enabled bool `config:"enabled" description:"Write the log entries in the background"`
+	// QueueSize size of the queue
+	QueueSize int `config:"queue-size" description:"Size of the log entries queue"`
+	// Overflow policy of the full queue
+	Overflow string `config:"overflow" description:"Policy of the full log entries queue: block, drop-newest or drop-oldest"`
+}
+
+// SamplingConfig configuration of the sampling logger bound from the `gluon.log.sampling` properties
+type SamplingConfig struct {
+	// Enabled sample the entries with the same level and message
+	Enabled bool `config:"enabled" description:"Sample the log entries with the same level and message"`
+	// Interval of the sampling, must be positive
+	Interval time.Duration `config:"interval" description:"Interval of the log sampling, must be positive"`
+	// First number of the written entries in the interval
+	First int `config:"first" description:"Number of the log entries with the same level and message written in the interval"`
+	// Thereafter write every n-th entry after the first entries
+	Thereafter int `config:"thereafter" description:"Write every n-th log entry after the first entries, zero drops them"`
+}
+
+// NewConfig returns the configuration with the current global levels
+func NewConfig() Config {
+	return Config{
+		Level:      GetLevel().String(),
+		Caller:     atomic.LoadInt32(&callerEnabled) == 1,
+		StackLevel: Level(atomic.LoadInt32(&stackLevel)).String(),
+		Output:     map[string]OutputConfig{},
+		Async:      AsyncConfig{QueueSize: 1024, Overflow: Block.String()},
+		Sampling:   SamplingConfig{Interval: time.Second, First: 100, Thereafter: 100},
+		Categories: map[string]string{},
+	}
+}
+
+// NewFormatter returns the formatter by the name: text, json, logfmt or console
+func NewFormatter(name string) (Formatter, error) {
+	return newFormatter(name, os.Stdout)
+}
+
+// newFormatter returns the formatter of the writer by the name, the console formatter uses
+// the colors only for the terminal writer
+func newFormatter(name string, w io.Writer) (Formatter, error) {
+	switch name {
+	case "", "text":
+		return TextFormatter{}, nil
+	case "json":
+		return JSONFormatter{}, nil
+	case "logfmt":
+		return LogfmtFormatter{}, nil
+	case "console":
+		return NewWriterConsoleFormatter(w), nil
+	}
+	return nil, fmt.Errorf("unknown log format: %q", name)
+}
+
+// Configure apply the levels, the format and the outputs of the configuration to the global logging
+func Configure(cfg Config) error {
+	l, levels, err := parseLevels(cfg)
+	if err != nil {
+		return err
+	}
+	stack := OffLevel
+	if len(cfg.StackLevel) > 0 {
+		stack, err = ParseLevel(cfg.StackLevel)
+		if err != nil {
+			return fmt.Errorf("stack level: %w", err)
+		}
+	}
+	base, err := newLogger(cfg)
+	if err != nil {
+		return err
+	}
+	previous := Log
+	owned := configured != nil && previous == configured
+	if !owned {
+		restore = previous
+	}
+	replaced := base != nil
+	if !replaced {
+		base = undecorated(previous)
+	}
+	logger, err := decorate(base, cfg)
+	if err != nil {
+		if replaced {
+			_ = closeLogger(base)
+		}
+		return err
+	}
+	Log = logger
+	if owned {
+		if replaced {
+			// the new outputs replace the outputs of the previous logger
+			_ = release(previous)
+		} else {
+			detach(previous, base)
+		}
+	}
+	configured = nil
+	if !sameLogger(logger, restore) {
+		configured = logger
+	}
+	global.set(l, levels)
+	SetCaller(cfg.Caller)
+	SetStackLevel(stack)
+	return nil
+}
+
+// Configure apply the level and the category levels of the configuration to the levels
+func (l *Levels) Configure(cfg Config) error {
+	level, categories, err := parseLevels(cfg)
+	if err != nil {
+		return err
+	}
+	l.set(level, categories)
+	return nil
+}
+
+// set the minimum level and the category levels
+func (l *Levels) set(level Level, categories map[string]Level) {
+	l.SetLevel(level)
+	for category, tmp := range categories {
+		l.SetCategoryLevel(category, tmp)
+	}
+}
+
+// parseLevels parse the level and the category levels of the configuration
+func parseLevels(cfg Config) (Level, map[string]Level, error) {
+	l, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return l, nil, err
+	}
+	levels := make(map[string]Level, len(cfg.Categories))
+	for category, name := range cfg.Categories {
+		tmp, err := ParseLevel(name)
+		if err != nil {
+			return l, nil, fmt.Errorf("category %s: %w", category, err)
+		}
+		levels[category] = tmp
+	}
+	return l, levels, nil
+}
+
+var (
+	// configured global logger installed by Configure
+	configured Logger
+	// restore global logger replaced by the first Configure, it is never closed by Configure
+	restore Logger
+)
+
+// Reset close the global logger installed by Configure and restore the global logger which was
+// replaced by Configure. The global logger which was not installed by Configure is kept.
+func Reset() error {
+	current := Log
+	if configured == nil || current != configured {
+		return nil
+	}
+	err := release(current)
+	Log = restore
+	if Log == nil {
+		Log = SimpleLogger{}
+	}
+	configured, restore = nil, nil
+	return err
+}
+
+// release close the logger installed by Configure except the replaced global logger
+func release(l Logger) error {
+	base := undecorated(l)
+	detach(l, base)
+	if sameLogger(base, restore) {
+		return nil
+	}
+	return closeLogger(base)
+}
+
+// sameLogger returns true for the same loggers, the loggers of the types which are not comparable
+// are never the same
+func sameLogger(a, b Logger) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || t != nil && !t.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// closeLogger close the logger which implements the io.Closer
+func closeLogger(l Logger) error {
+	if c, ok := l.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// newLogger create the logger of the outputs or the format, returns nil to keep the current logger
+func newLogger(cfg Config) (Logger, error) {
+	if len(cfg.Outputs) == 0 {
+		if len(cfg.Format) == 0 {
+			return nil, nil
+		}
+		formatter, err := NewFormatter(cfg.Format)
+		if err != nil {
+			return nil, err
+		}
+		return NewFormatLogger(os.Stdout, formatter), nil
+	}
+
+	sinks := make([]Sink, 0, len(cfg.Outputs))
+	for _, name := range cfg.Outputs {
+		sink, err := newSink(name, cfg.Output[name], cfg.Format)
+		if err != nil {
+			return nil, fmt.Errorf("log output %s: %w", name, err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return NewSinkLogger(sinks...), nil
+}
+
+func newSink(name string, cfg OutputConfig, format string) (Sink, error) {
+	if len(cfg.Format) > 0 {
+		format = cfg.Format
+	}
+	level := TraceLevel
+	if len(cfg.Level) > 0 {
+		var err error
+		level, err = ParseLevel(cfg.Level)
+		if err != nil {
+			return Sink{}, err
+		}
+	}
+
+	typeName := cfg.Type
+	if len(typeName) == 0 {
+		typeName = name
+	}
+	var w io.Writer
+	switch typeName {
+	case "stdout":
+		w = os.Stdout
+	case "stderr":
+		w = os.Stderr
+	case "file":
+		if len(cfg.Path) == 0 {
+			return Sink{}, errors.New("missing path of the file")
+		}
+		w = &RotatingFile{Path: cfg.Path, MaxSize: cfg.MaxSize, Interval: cfg.Interval, MaxBackups: cfg.MaxBackups, Compress: cfg.Compress}
+	default:
+		return Sink{}, fmt.Errorf("unknown type: %q", typeName)
+	}
+	formatter, err := newFormatter(format, w)
+	if err != nil {
+		return Sink{}, err
+	}
+	return Sink{Writer: w, Formatter: formatter, Level: level}, nil
+}
+
+// decorate wrap the logger with the asynchronous and the sampling logger, the sampling logger
+// is the outer one so the suppressed entries never reach the queue
+func decorate(logger Logger, cfg Config) (Logger, error) {
+	policy, err := ParseOverflowPolicy(cfg.Async.Overflow)
+	if err != nil && cfg.Async.Enabled {
+		return nil, err
+	}
+	if cfg.Sampling.Enabled && cfg.Sampling.Interval <= 0 {
+		return nil, fmt.Errorf("sampling interval must be positive: %s", cfg.Sampling.Interval)
+	}
+	if cfg.Async.Enabled {
+		logger = NewAsyncLogger(logger, cfg.Async.QueueSize, policy)
+	}
+	if cfg.Sampling.Enabled {
+		logger = NewSamplingLogger(logger, cfg.Sampling.Interval, cfg.Sampling.First, cfg.Sampling.Thereafter)
+	}
+	return logger, nil
+}
+
+// undecorated returns the logger without the asynchronous and the sampling loggers installed
+// by Configure
+func undecorated(l Logger) Logger {
+	for !sameLogger(l, restore) {
+		switch tmp := l.(type) {
+		case *AsyncLogger:
+			l = tmp.target
+		case *SamplingLogger:
+			l = tmp.target
+		default:
+			return l
+		}
+	}
+	return l
+}
+
+// detach stop the decorators of the logger from the outer one down to the base logger
+func detach(l, base Logger) {
+	for !sameLogger(l, base) {
+		switch tmp := l.(type) {
+		case *AsyncLogger:
+			tmp.detach()
+			l = tmp.target
+		case *SamplingLogger:
+			tmp.detach()
+			l = tmp.target
+		default:
+			return
+		}
+	}
+}
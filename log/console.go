@@ -0,0 +1,94 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	colorReset = "\x1b[0m"
+	colorGray  = "\x1b[90m"
+)
+
+var levelColors = map[Level]string{
+	TraceLevel: "\x1b[90m",
+	DebugLevel: "\x1b[36m",
+	InfoLevel:  "\x1b[32m",
+	WarnLevel:  "\x1b[33m",
+	ErrorLevel: "\x1b[31m",
+}
+
+// ConsoleFormatter human-friendly format for the development with the aligned levels
+// and the fields on separate indented lines
+type ConsoleFormatter struct {
+	// Color use the ANSI colors for the levels and the field names
+	Color bool
+	// TimeFormat format of the time, default `15:04:05.000`
+	TimeFormat string
+}
+
+// NewConsoleFormatter create console formatter which uses the colors only if the standard
+// output is a terminal and the `NO_COLOR` environment variable is not set
+func NewConsoleFormatter() ConsoleFormatter {
+	return NewWriterConsoleFormatter(os.Stdout)
+}
+
+// NewWriterConsoleFormatter create console formatter which uses the colors only if the writer
+// is a terminal and the `NO_COLOR` environment variable is not set
+func NewWriterConsoleFormatter(w io.Writer) ConsoleFormatter {
+	f, ok := w.(*os.File)
+	return ConsoleFormatter{Color: ok && IsTerminal(f) && len(os.Getenv("NO_COLOR")) == 0}
+}
+
+// IsTerminal returns true if the file is a character device like the terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (c ConsoleFormatter) Format(e *Entry) []byte {
+	timeFormat := c.TimeFormat
+	if len(timeFormat) == 0 {
+		timeFormat = "15:04:05.000"
+	}
+
+	b := &bytes.Buffer{}
+	c.color(b, colorGray, e.Time.Format(timeFormat))
+	b.WriteByte(' ')
+	c.color(b, levelColors[e.Level], fmt.Sprintf("%-5s", strings.ToUpper(e.Level.String())))
+	b.WriteByte(' ')
+	if e.Caller != nil {
+		c.color(b, colorGray, e.Caller.String())
+		b.WriteByte(' ')
+	}
+	if len(e.Category) > 0 {
+		c.color(b, colorGray, e.Category+":")
+		b.WriteByte(' ')
+	}
+	b.WriteString(e.Message)
+	b.WriteByte('\n')
+
+	fields := entryFields(e)
+	for _, k := range fields.Keys() {
+		b.WriteString("    ")
+		c.color(b, colorGray, k+":")
+		b.WriteByte(' ')
+		b.WriteString(strings.ReplaceAll(formatValue(fields[k]), "\n", "\n      "))
+		b.WriteByte('\n')
+	}
+	writeStack(b, e.Stack)
+	return b.Bytes()
+}
+
+func (c ConsoleFormatter) color(b *bytes.Buffer, color, text string) {
+	if !c.Color || len(color) == 0 {
+		b.WriteString(text)
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(text)
+	b.WriteString(colorReset)
+}
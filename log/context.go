@@ -0,0 +1,142 @@
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor returns the correlation fields of the context, for example the request ID,
+// the trace ID and the span ID
+type ContextExtractor func(ctx context.Context) map[string]interface{}
+
+type loggerKey struct{}
+
+type fieldsKey struct{}
+
+type contextExtractor struct {
+	id uint64
+	fn ContextExtractor
+}
+
+var (
+	extractorsMutex sync.RWMutex
+	extractors      []contextExtractor
+	extractorID     uint64
+)
+
+// RegisterContextExtractor register the extractor of the correlation fields used by FromContext,
+// the returned function unregisters the extractor
+func RegisterContextExtractor(fn ContextExtractor) func() {
+	extractorsMutex.Lock()
+	defer extractorsMutex.Unlock()
+	extractorID++
+	id := extractorID
+	extractors = append(extractors, contextExtractor{id: id, fn: fn})
+	return func() {
+		extractorsMutex.Lock()
+		defer extractorsMutex.Unlock()
+		for i, item := range extractors {
+			if item.id == id {
+				// the new slice, the copies of the previous slice are not changed
+				extractors = append(extractors[:i:i], extractors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// WithContext returns the context with the logger
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// ContextWithFields returns the context with the correlation fields added to the fields
+// of the parent context
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, MergeFields(contextValueFields(ctx), fields))
+}
+
+// ContextFields returns the correlation fields of the context and the registered extractors
+func ContextFields(ctx context.Context) Fields {
+	if ctx == nil {
+		return Fields{}
+	}
+	extractorsMutex.RLock()
+	items := extractors
+	extractorsMutex.RUnlock()
+
+	result := MergeFields(contextValueFields(ctx))
+	for _, item := range items {
+		for k, v := range item.fn(ctx) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// FromContext returns the logger of the context or the global logger with the correlation
+// fields of the context
+func FromContext(ctx context.Context) Logger {
+	logger := contextLogger(ctx)
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		if logger == nil {
+			return From(nil)
+		}
+		return logger
+	}
+	return From(logger).With(fields)
+}
+
+// contextLogger returns the logger of the context or nil
+func contextLogger(ctx context.Context) Logger {
+	if ctx == nil {
+		return nil
+	}
+	logger, _ := ctx.Value(loggerKey{}).(Logger)
+	return logger
+}
+
+// enabledCtx returns true if the logger of the context writes the level, the correlation fields
+// are collected only for the enabled levels
+func enabledCtx(ctx context.Context, level Level) bool {
+	return From(contextLogger(ctx)).IsEnabled(level)
+}
+
+func contextValueFields(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+	return fields
+}
+
+func TraceCtx(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if enabledCtx(ctx, TraceLevel) {
+		FromContext(ctx).Trace(msg, fields...)
+	}
+}
+
+func DebugCtx(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if enabledCtx(ctx, DebugLevel) {
+		FromContext(ctx).Debug(msg, fields...)
+	}
+}
+
+func InfoCtx(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if enabledCtx(ctx, InfoLevel) {
+		FromContext(ctx).Info(msg, fields...)
+	}
+}
+
+func WarnCtx(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if enabledCtx(ctx, WarnLevel) {
+		FromContext(ctx).Warn(msg, fields...)
+	}
+}
+
+func ErrorCtx(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if enabledCtx(ctx, ErrorLevel) {
+		FromContext(ctx).Error(msg, fields...)
+	}
+}
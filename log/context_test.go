@@ -0,0 +1,54 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type traceKey struct{}
+
+func TestContextLogger(t *testing.T) {
+	original := Log
+	l := &entries{}
+	Log = l
+	t.Cleanup(func() {
+		Log = original
+	})
+	calls := 0
+	t.Cleanup(RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		calls++
+		if id, ok := ctx.Value(traceKey{}).(string); ok {
+			return Fields{"trace_id": id}
+		}
+		return nil
+	}))
+
+	ctx := context.Background()
+	InfoCtx(ctx, "plain")
+	assert.Len(t, l.items[0].Fields, 0)
+
+	ctx = ContextWithFields(ctx, Fields{"request_id": "r1"})
+	ctx = ContextWithFields(ctx, Fields{"tenant": "a"})
+	ctx = context.WithValue(ctx, traceKey{}, "t1")
+	WarnCtx(ctx, "slow", Add("ms", 10))
+	assert.Equal(t, Fields{"request_id": "r1", "tenant": "a", "trace_id": "t1", "ms": 10}, MergeFields(l.items[1].Fields...))
+
+	ctx = WithContext(ctx, Named("orders"))
+	ErrorCtx(ctx, "failed")
+	assert.Equal(t, "orders", l.items[2].Category)
+	assert.Equal(t, Fields{"request_id": "r1", "tenant": "a", "trace_id": "t1"}, MergeFields(l.items[2].Fields...))
+
+	DebugCtx(ctx, "skipped")
+	assert.Len(t, l.items, 3)
+	assert.Equal(t, 3, calls)
+
+	unregister := RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		return Fields{"span_id": "s1"}
+	})
+	assert.Equal(t, "s1", ContextFields(ctx)["span_id"])
+	unregister()
+	unregister()
+	assert.Equal(t, Fields{"request_id": "r1", "tenant": "a", "trace_id": "t1"}, ContextFields(ctx))
+}
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Formatter format the log entry to one line with the line separator
+type Formatter interface {
+	Format(e *Entry) []byte
+}
+
+// FormatLogger writes the entries which pass the global level to the writer with the formatter
+type FormatLogger struct {
+	mutex     sync.Mutex
+	w         io.Writer
+	formatter Formatter
+}
+
+// NewFormatLogger create logger which writes the entries formatted by the formatter to the writer
+func NewFormatLogger(w io.Writer, formatter Formatter) *FormatLogger {
+	return &FormatLogger{w: w, formatter: formatter}
+}
+
+func (l *FormatLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(TraceLevel, msg, fields)
+}
+
+func (l *FormatLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(DebugLevel, msg, fields)
+}
+
+func (l *FormatLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(InfoLevel, msg, fields)
+}
+
+func (l *FormatLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(WarnLevel, msg, fields)
+}
+
+func (l *FormatLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(ErrorLevel, msg, fields)
+}
+
+func (l *FormatLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	if IsEnabled(level) {
+		l.WriteEntry(newEntry(level, msg, fields))
+	}
+}
+
+// WriteEntry writes the entry without the level check
+func (l *FormatLogger) WriteEntry(e *Entry) {
+	d := l.formatter.Format(e)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_, _ = l.w.Write(d)
+}
+
+// TextFormatter formats the entry as `[LEVEL] caller category: message [fields]` followed by the stack trace
+type TextFormatter struct {
+}
+
+func (TextFormatter) Format(e *Entry) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "%7s ", "["+strings.ToUpper(e.Level.String())+"]")
+	if e.Caller != nil {
+		b.WriteString(e.Caller.String())
+		b.WriteByte(' ')
+	}
+	if len(e.Category) > 0 {
+		b.WriteString(e.Category)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	if fields := entryFields(e); len(fields) > 0 {
+		b.WriteByte(' ')
+		b.WriteString(fmt.Sprint([]map[string]interface{}{fields}))
+	}
+	b.WriteByte('\n')
+	writeStack(b, e.Stack)
+	return b.Bytes()
+}
+
+// MergeFields merge the fields into one map, the later fields override the previous ones
+func MergeFields(fields ...map[string]interface{}) Fields {
+	result := Fields{}
+	for _, f := range fields {
+		for k, v := range f {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Keys sorted names of the fields
+func (f Fields) Keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldName returns the function which adds the `fields.` prefix to the reserved names of the entry keys
+func fieldName(reserved map[string]bool) func(string) string {
+	return func(name string) string {
+		if reserved[name] {
+			return "fields." + name
+		}
+		return name
+	}
+}
+
+// renameFields returns the fields with the names changed by the function, the renamed field does
+// not replace the field with the same name
+func renameFields(fields Fields, name func(string) string) Fields {
+	result := make(Fields, len(fields))
+	for k, v := range fields {
+		if name(k) == k {
+			result[k] = v
+		}
+	}
+	for k, v := range fields {
+		tmp := name(k)
+		if _, exists := result[tmp]; !exists {
+			result[tmp] = v
+		}
+	}
+	return result
+}
+
+// writeStack writes the indented stack trace lines
+func writeStack(b *bytes.Buffer, stack string) {
+	if len(stack) == 0 {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(stack, "\n"), "\n") {
+		b.WriteString("    ")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var formatEntry = &Entry{
+	Time:     time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+	Level:    WarnLevel,
+	Category: "orders",
+	Message:  "failed order",
+	Fields: []map[string]interface{}{
+		Err(errors.New("line 1\nline 2")).Add("id", 1),
+		{"id": 2, "note": `say "hi"`, "empty": "", "key=x": "a=b", "ts": "shadow"},
+	},
+}
+
+func TestTextFormatter(t *testing.T) {
+	assert.Equal(t, " [WARN] orders: failed order\n", string(TextFormatter{}.Format(&Entry{Level: WarnLevel, Category: "orders", Message: "failed order"})))
+	assert.Equal(t, "[DEBUG] started [map[id:1]]\n", string(TextFormatter{}.Format(&Entry{Level: DebugLevel, Message: "started", Fields: []map[string]interface{}{Add("id", 1)}})))
+}
+
+func TestLogfmtFormatter(t *testing.T) {
+	assert.Equal(t, `ts=2021-03-04T05:06:07Z level=warn category=orders msg="failed order" empty="" `+
+		`error="line 1\nline 2" fields.ts=shadow id=2 key_x="a=b" note="say \"hi\""`+"\n", string(LogfmtFormatter{}.Format(formatEntry)))
+}
+
+func TestConsoleFormatter(t *testing.T) {
+	assert.Equal(t, "05:06:07.000 WARN  orders: failed order\n"+
+		"    empty: \n"+
+		"    error: line 1\n      line 2\n"+
+		"    id: 2\n"+
+		"    key=x: a=b\n"+
+		"    note: say \"hi\"\n"+
+		"    ts: shadow\n", string(ConsoleFormatter{}.Format(formatEntry)))
+
+	colored := string(ConsoleFormatter{Color: true}.Format(&Entry{Level: ErrorLevel, Message: "failed"}))
+	assert.Equal(t, "\x1b[90m00:00:00.000\x1b[0m \x1b[31mERROR\x1b[0m failed\n", colored)
+}
+
+func TestFormatLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := NewFormatLogger(b, TextFormatter{})
+	l.Debug("skipped", Add("id", 1))
+	l.Info("started", Add("id", 1))
+	assert.Equal(t, " [INFO] started [map[id:1]]\n", b.String())
+}
+
+func TestFormatTypedNil(t *testing.T) {
+	var err *os.PathError
+	var u *url.URL
+	e := &Entry{Time: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Level: ErrorLevel, Message: "failed", Fields: []map[string]interface{}{
+		Err(err).Add("url", u),
+	}}
+	assert.Equal(t, "ts=2021-03-04T05:06:07Z level=error msg=failed error=<nil> url=<nil>\n", string(LogfmtFormatter{}.Format(e)))
+	assert.Equal(t, "05:06:07.000 ERROR failed\n    error: <nil>\n    url: <nil>\n", string(ConsoleFormatter{}.Format(e)))
+}
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+// JSONLogger writes the entries which pass the global level with the JSONFormatter
+type JSONLogger struct {
+	FormatLogger
+}
+
+// NewJSONLogger create JSON logger which writes to the writer
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{FormatLogger: FormatLogger{w: w, formatter: JSONFormatter{}}}
+}
+
+// JSONFormatter formats the entry as one JSON object per line. The object starts with the `time`,
+// `level`, `category`, `caller`, `function`, `msg` and `stack` keys followed by the fields sorted
+// by the name. The fields with the same name as these keys get the `fields.` prefix.
+type JSONFormatter struct {
+}
+
+// jsonKeys reserved keys of the JSON object
+var jsonKeys = map[string]bool{"time": true, "level": true, "category": true, "caller": true, "function": true, "msg": true, "stack": true}
+
+func (JSONFormatter) Format(e *Entry) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString(`{"time":`)
+	writeJSON(b, e.Time.Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSON(b, e.Level.String())
+	if len(e.Category) > 0 {
+		b.WriteString(`,"category":`)
+		writeJSON(b, e.Category)
+	}
+	if e.Caller != nil {
+		b.WriteString(`,"caller":`)
+		writeJSON(b, e.Caller.String())
+		b.WriteString(`,"function":`)
+		writeJSON(b, e.Caller.Function)
+	}
+	b.WriteString(`,"msg":`)
+	writeJSON(b, e.Message)
+	if len(e.Stack) > 0 {
+		b.WriteString(`,"stack":`)
+		writeJSON(b, e.Stack)
+	}
+
+	fields := renameFields(entryFields(e), fieldName(jsonKeys))
+	for _, k := range fields.Keys() {
+		b.WriteByte(',')
+		writeJSON(b, k)
+		b.WriteByte(':')
+		writeJSON(b, jsonValue(fields[k]))
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// jsonValue replace the errors by the error message also inside the maps, the slices and the arrays,
+// the raw errors are serialized as empty objects. The nil pointer errors are null.
+func jsonValue(value interface{}) interface{} {
+	if err, ok := value.(error); ok {
+		if isNil(err) {
+			return nil
+		}
+		return err.Error()
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if !hasErrors(v.Type().Elem()) || v.IsNil() {
+			return value
+		}
+		result := make(map[string]interface{}, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			result[fmt.Sprint(it.Key().Interface())] = jsonValue(it.Value().Interface())
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if !hasErrors(v.Type().Elem()) || v.Kind() == reflect.Slice && v.IsNil() {
+			return value
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			result[i] = jsonValue(v.Index(i).Interface())
+		}
+		return result
+	}
+	return value
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// hasErrors returns true if the values of the type may contain the errors
+func hasErrors(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return t.Implements(errorType)
+}
+
+// isNil returns true for the nil pointer of the error or the fmt.Stringer type
+func isNil(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// writeJSON writes the serialized value or the formatted value if it can't be serialized
+func writeJSON(b *bytes.Buffer, value interface{}) {
+	d, err := json.Marshal(value)
+	if err != nil {
+		d, _ = json.Marshal(fmt.Sprintf("%+v", value))
+	}
+	b.Write(d)
+}
@@ -0,0 +1,40 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := NewJSONLogger(b)
+	tm := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	l.WriteEntry(&Entry{Time: tm, Level: WarnLevel, Category: "orders", Message: "failed \"order\"", Fields: []map[string]interface{}{
+		Err(errors.New("timeout")).Add("id", 1).Add("msg", "shadow"),
+		{"id": 2, "raw": math.NaN(), "list": []string{"a"}, "ts": 1},
+	}})
+	assert.Equal(t, `{"time":"2021-03-04T05:06:07Z","level":"warn","category":"orders","msg":"failed \"order\"",`+
+		`"error":"timeout","fields.msg":"shadow","id":2,"list":["a"],"raw":"NaN","ts":1}`+"\n", b.String())
+
+	b.Reset()
+	l.Debug("skipped")
+	assert.Empty(t, b.String())
+	l.Info("started")
+	assert.Contains(t, b.String(), `"level":"info","msg":"started"}`+"\n")
+
+	b.Reset()
+	var missing *os.PathError
+	l.WriteEntry(&Entry{Time: tm, Level: ErrorLevel, Message: "nested", Fields: []map[string]interface{}{{
+		"errors": []error{errors.New("a"), missing},
+		"nested": map[string]interface{}{"error": errors.New("b"), "list": []interface{}{errors.New("c")}},
+	}}})
+	assert.Equal(t, `{"time":"2021-03-04T05:06:07Z","level":"error","msg":"nested",`+
+		`"errors":["a",null],"nested":{"error":"b","list":["c"]}}`+"\n", b.String())
+}
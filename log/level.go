@@ -0,0 +1,215 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level severity of the log entry
+type Level int32
+
+const (
+	TraceLevel Level = iota
+	DebugLevel
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	// OffLevel minimum level which disables the logging
+	OffLevel
+)
+
+var levelNames = []string{"trace", "debug", "info", "warn", "error", "off"}
+
+func (l Level) String() string {
+	if l < TraceLevel || l > OffLevel {
+		return fmt.Sprintf("level(%d)", int32(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parse the case-insensitive level name
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		return WarnLevel, nil
+	}
+	for i, item := range levelNames {
+		if item == name {
+			return Level(i), nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("unknown log level: %q", name)
+}
+
+var global = NewLevels(InfoLevel)
+
+// Levels minimum level and the minimum levels of the categories. The package functions use
+// the global levels, the LevelLogger applies its own levels.
+type Levels struct {
+	level      int32
+	mutex      sync.RWMutex
+	categories map[string]Level
+}
+
+// NewLevels create the levels with the minimum level and without the category levels
+func NewLevels(l Level) *Levels {
+	return &Levels{level: int32(l), categories: map[string]Level{}}
+}
+
+// SetLevel set the minimum level
+func (l *Levels) SetLevel(level Level) {
+	atomic.StoreInt32(&l.level, int32(level))
+}
+
+// GetLevel returns the minimum level
+func (l *Levels) GetLevel() Level {
+	return Level(atomic.LoadInt32(&l.level))
+}
+
+// SetCategoryLevel set the minimum level of the category and all its dotted sub-categories
+func (l *Levels) SetCategoryLevel(category string, level Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.categories[category] = level
+}
+
+// RemoveCategoryLevel remove the minimum level of the category
+func (l *Levels) RemoveCategoryLevel(category string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.categories, category)
+}
+
+// CategoryLevel returns the minimum level of the category from the longest matching
+// category prefix or the minimum level
+func (l *Levels) CategoryLevel(category string) Level {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	for name := category; len(l.categories) > 0 && len(name) > 0; {
+		if tmp, exists := l.categories[name]; exists {
+			return tmp
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.GetLevel()
+}
+
+// IsEnabled returns true if the level passes the minimum level
+func (l *Levels) IsEnabled(level Level) bool {
+	return level >= l.GetLevel() && level < OffLevel
+}
+
+// IsCategoryEnabled returns true if the level passes the minimum level of the category
+func (l *Levels) IsCategoryEnabled(category string, level Level) bool {
+	return level >= l.CategoryLevel(category) && level < OffLevel
+}
+
+// SetLevel set the global minimum level, default InfoLevel
+func SetLevel(l Level) {
+	global.SetLevel(l)
+}
+
+// GetLevel returns the global minimum level
+func GetLevel() Level {
+	return global.GetLevel()
+}
+
+// SetCategoryLevel set the minimum level of the category and all its dotted sub-categories,
+// for example the level of `orders` applies to the `orders.db` category
+func SetCategoryLevel(category string, l Level) {
+	global.SetCategoryLevel(category, l)
+}
+
+// RemoveCategoryLevel remove the minimum level of the category
+func RemoveCategoryLevel(category string) {
+	global.RemoveCategoryLevel(category)
+}
+
+// CategoryLevel returns the minimum level of the category from the longest matching
+// category prefix or the global level
+func CategoryLevel(category string) Level {
+	return global.CategoryLevel(category)
+}
+
+// IsEnabled returns true if the level passes the global minimum level. Use it to skip
+// building expensive fields.
+func IsEnabled(l Level) bool {
+	return global.IsEnabled(l)
+}
+
+// IsCategoryEnabled returns true if the level passes the minimum level of the category
+func IsCategoryEnabled(category string, l Level) bool {
+	return global.IsCategoryEnabled(category, l)
+}
+
+// levelsOf returns the levels of the logger or the global levels, nil logger means the global logger Log
+func levelsOf(l Logger) *Levels {
+	if l == nil {
+		l = Log
+	}
+	if tmp, ok := l.(*LevelLogger); ok {
+		return tmp.levels
+	}
+	return global
+}
+
+// LevelLogger applies its own levels instead of the global levels to the entries of the target
+// logger, the named child loggers of it check the category levels of it
+type LevelLogger struct {
+	target Logger
+	levels *Levels
+}
+
+// NewLevelLogger create the logger which writes the entries which pass the levels to the target logger
+func NewLevelLogger(target Logger, levels *Levels) *LevelLogger {
+	return &LevelLogger{target: target, levels: levels}
+}
+
+// Levels of the logger
+func (l *LevelLogger) Levels() *Levels {
+	return l.levels
+}
+
+func (l *LevelLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(TraceLevel, msg, fields)
+}
+
+func (l *LevelLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(DebugLevel, msg, fields)
+}
+
+func (l *LevelLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(InfoLevel, msg, fields)
+}
+
+func (l *LevelLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(WarnLevel, msg, fields)
+}
+
+func (l *LevelLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(ErrorLevel, msg, fields)
+}
+
+func (l *LevelLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	if l.levels.IsEnabled(level) {
+		l.WriteEntry(newEntry(level, msg, fields))
+	}
+}
+
+// WriteEntry writes the entry to the target logger without the level check
+func (l *LevelLogger) WriteEntry(e *Entry) {
+	write(l.target, e)
+}
+
+// Flush the target logger
+func (l *LevelLogger) Flush() {
+	if f, ok := l.target.(Flusher); ok {
+		f.Flush()
+	}
+}
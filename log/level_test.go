@@ -0,0 +1,83 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type entries struct {
+	items []*Entry
+}
+
+func (l *entries) Trace(msg string, fields ...map[string]interface{}) {}
+func (l *entries) Debug(msg string, fields ...map[string]interface{}) {}
+func (l *entries) Info(msg string, fields ...map[string]interface{})  {}
+func (l *entries) Warn(msg string, fields ...map[string]interface{})  {}
+func (l *entries) Error(msg string, fields ...map[string]interface{}) {}
+func (l *entries) WriteEntry(e *Entry)                                { l.items = append(l.items, e) }
+
+func TestParseLevel(t *testing.T) {
+	for _, l := range []Level{TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, OffLevel} {
+		tmp, err := ParseLevel(l.String())
+		assert.Nil(t, err)
+		assert.Equal(t, l, tmp)
+	}
+	l, err := ParseLevel(" WARNING")
+	assert.Nil(t, err)
+	assert.Equal(t, WarnLevel, l)
+	_, err = ParseLevel("verbose")
+	assert.EqualError(t, err, `unknown log level: "verbose"`)
+}
+
+func TestCategoryLevel(t *testing.T) {
+	original := Log
+	l := &entries{}
+	Log = l
+	t.Cleanup(func() {
+		Log = original
+		SetLevel(InfoLevel)
+		RemoveCategoryLevel("orders")
+		RemoveCategoryLevel("orders.db")
+	})
+
+	err := Configure(Config{Level: "warn", Categories: map[string]string{"orders": "error", "orders.db": "debug"}})
+	assert.Nil(t, err)
+	assert.False(t, IsEnabled(InfoLevel))
+	assert.True(t, IsEnabled(WarnLevel))
+	assert.Equal(t, DebugLevel, CategoryLevel("orders.db.pool"))
+	assert.Equal(t, ErrorLevel, CategoryLevel("orders.api"))
+	assert.Equal(t, WarnLevel, CategoryLevel("ordersdb"))
+
+	Category("orders.db").Debug("query", Add("table", "orders"))
+	Category("orders.db").Trace("skipped")
+	Category("orders").Warn("skipped")
+	Category("other").Warn("slow")
+	assert.Len(t, l.items, 2)
+	assert.Equal(t, "orders.db", l.items[0].Category)
+	assert.Equal(t, DebugLevel, l.items[0].Level)
+	assert.Equal(t, []map[string]interface{}{{"table": "orders"}}, l.items[0].Fields)
+	assert.Equal(t, "slow", l.items[1].Message)
+
+	err = Configure(Config{Level: "info", Categories: map[string]string{"orders": "loud"}})
+	assert.EqualError(t, err, `category orders: unknown log level: "loud"`)
+	assert.Equal(t, WarnLevel, GetLevel())
+}
+
+func TestLevelLogger(t *testing.T) {
+	l := &entries{}
+	levels := NewLevels(ErrorLevel)
+	err := levels.Configure(Config{Level: "warn", Categories: map[string]string{"orders": "debug"}})
+	assert.Nil(t, err)
+	logger := NewLevelLogger(l, levels)
+
+	logger.Info("skipped")
+	logger.Warn("written")
+	From(logger).Named("orders").Debug("query")
+	From(logger).Named("other").Debug("skipped")
+	assert.Len(t, l.items, 2)
+	assert.Equal(t, "written", l.items[0].Message)
+	assert.Equal(t, "orders", l.items[1].Category)
+	assert.Equal(t, InfoLevel, GetLevel())
+	assert.False(t, IsCategoryEnabled("orders", DebugLevel))
+}
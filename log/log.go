@@ -1,7 +1,8 @@
 package log
 
 import (
-	"fmt"
+	"os"
+	"time"
 )
 
 var (
@@ -60,28 +61,100 @@ type Logger interface {
 	Error(msg string, fields ...map[string]interface{})
 }
 
+// Entry log entry which passed the level check
+type Entry struct {
+	Time  time.Time
+	Level Level
+	// Category of the logger, empty for the global logger
+	Category string
+	Message  string
+	Fields   []map[string]interface{}
+	// Caller location of the log call if it is enabled by SetCaller
+	Caller *Caller
+	// Stack trace of the log call if the level is enabled by SetStackLevel
+	Stack string
+}
+
+// EntryWriter is implemented by the loggers which write the entries without the global level
+// check, the child loggers use it to apply the level of the category
+type EntryWriter interface {
+	WriteEntry(e *Entry)
+}
+
+// write the entry to the logger, the loggers which do not implement the EntryWriter
+// get the category, the caller and the stack trace as the fields
+func write(l Logger, e *Entry) {
+	if w, ok := l.(EntryWriter); ok {
+		w.WriteEntry(e)
+		return
+	}
+	fields := map[string]interface{}{}
+	if len(e.Category) > 0 {
+		fields["category"] = e.Category
+	}
+	if e.Caller != nil {
+		fields["caller"] = e.Caller.String()
+		fields["function"] = e.Caller.Function
+	}
+	if len(e.Stack) > 0 {
+		fields["stack"] = e.Stack
+	}
+	if len(fields) > 0 {
+		e.Fields = append([]map[string]interface{}{fields}, e.Fields...)
+	}
+	switch e.Level {
+	case TraceLevel:
+		l.Trace(e.Message, e.Fields...)
+	case DebugLevel:
+		l.Debug(e.Message, e.Fields...)
+	case InfoLevel:
+		l.Info(e.Message, e.Fields...)
+	case WarnLevel:
+		l.Warn(e.Message, e.Fields...)
+	case ErrorLevel:
+		l.Error(e.Message, e.Fields...)
+	}
+}
+
+// newEntry create the entry with the current time, the caller and the stack trace
+func newEntry(level Level, msg string, fields []map[string]interface{}) *Entry {
+	e := &Entry{Time: time.Now(), Level: level, Message: msg, Fields: fields}
+	capture(e)
+	return e
+}
+
+// SimpleLogger writes the entries which pass the global level to the standard output
+// with the TextFormatter
 type SimpleLogger struct {
 }
 
 func (d SimpleLogger) Trace(msg string, fields ...map[string]interface{}) {
-	fmt.Printf("[TRACE] %s %v\n", msg, fmt.Sprint(fields))
+	d.log(TraceLevel, msg, fields)
 }
 
 func (d SimpleLogger) Debug(msg string, fields ...map[string]interface{}) {
-	if len(fields) > 0 {
-		fmt.Printf("[DEBUG] %s %v\n", msg, fmt.Sprint(fields))
-	}
-	fmt.Printf("[DEBUG] %s\n", msg)
+	d.log(DebugLevel, msg, fields)
 }
 
 func (d SimpleLogger) Info(msg string, fields ...map[string]interface{}) {
-	fmt.Printf(" [INFO] %s %v\n", msg, fmt.Sprint(fields))
+	d.log(InfoLevel, msg, fields)
 }
 
 func (d SimpleLogger) Warn(msg string, fields ...map[string]interface{}) {
-	fmt.Printf(" [WARN] %s %v\n", msg, fmt.Sprint(fields))
+	d.log(WarnLevel, msg, fields)
 }
 
 func (d SimpleLogger) Error(msg string, fields ...map[string]interface{}) {
-	fmt.Printf("[ERROR] %s %v\n", msg, fmt.Sprint(fields))
+	d.log(ErrorLevel, msg, fields)
+}
+
+func (d SimpleLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	if IsEnabled(level) {
+		d.WriteEntry(newEntry(level, msg, fields))
+	}
+}
+
+// WriteEntry writes the entry without the level check
+func (d SimpleLogger) WriteEntry(e *Entry) {
+	_, _ = os.Stdout.Write(TextFormatter{}.Format(e))
 }
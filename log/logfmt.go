@@ -0,0 +1,108 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// LogfmtFormatter formats the entry as logfmt line `ts=... level=info msg="..." key=value`.
+// The fields are sorted by the name and the values with spaces, quotes, `=` or control
+// characters are quoted. The fields with the same name as the entry keys get the `fields.` prefix.
+type LogfmtFormatter struct {
+}
+
+// logfmtKeys reserved keys of the logfmt line
+var logfmtKeys = map[string]bool{"ts": true, "level": true, "category": true, "caller": true, "function": true, "msg": true, "stack": true}
+
+func (LogfmtFormatter) Format(e *Entry) []byte {
+	b := &bytes.Buffer{}
+	writeLogfmt(b, "ts", e.Time.Format(time.RFC3339Nano))
+	b.WriteByte(' ')
+	writeLogfmt(b, "level", e.Level.String())
+	if len(e.Category) > 0 {
+		b.WriteByte(' ')
+		writeLogfmt(b, "category", e.Category)
+	}
+	if e.Caller != nil {
+		b.WriteByte(' ')
+		writeLogfmt(b, "caller", e.Caller.String())
+		b.WriteByte(' ')
+		writeLogfmt(b, "function", e.Caller.Function)
+	}
+	b.WriteByte(' ')
+	writeLogfmt(b, "msg", e.Message)
+	if len(e.Stack) > 0 {
+		b.WriteByte(' ')
+		writeLogfmt(b, "stack", e.Stack)
+	}
+
+	fields := renameFields(entryFields(e), fieldName(logfmtKeys))
+	for _, k := range fields.Keys() {
+		b.WriteByte(' ')
+		writeLogfmt(b, k, fields[k])
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+func writeLogfmt(b *bytes.Buffer, key string, value interface{}) {
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+	s := formatValue(value)
+	if needsQuote(s) {
+		s = strconv.Quote(s)
+	}
+	b.WriteString(s)
+}
+
+// logfmtKey replace the characters which are not allowed in the key by `_`
+func logfmtKey(key string) string {
+	if len(key) == 0 {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func needsQuote(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f || r == utf8.RuneError {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue format the field value as text
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case error:
+		if isNil(v) {
+			return "<nil>"
+		}
+		return v.Error()
+	case fmt.Stringer:
+		if isNil(v) {
+			return "<nil>"
+		}
+		return v.String()
+	}
+	return fmt.Sprintf("%+v", value)
+}
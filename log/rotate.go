@@ -0,0 +1,252 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat time suffix of the backup files which sorts by the time
+const backupTimeFormat = "20060102-150405.000"
+
+// RotatingFile file writer which renames the file to the backup `<path>.<time>` when the file
+// reaches the maximum size or the time crosses the interval boundary, for example the midnight
+// (UTC) for the 24h interval. The time of the backup is the time when the file was opened, so
+// the backup is named for the period it covers. The backups with the same time get the `.<n>` suffix.
+type RotatingFile struct {
+	// Path of the file
+	Path string
+	// MaxSize maximum size of the file in bytes, zero disables the size rotation
+	MaxSize int64
+	// Interval of the time rotation, zero disables the time rotation
+	Interval time.Duration
+	// MaxBackups number of the kept backups, zero keeps all backups
+	MaxBackups int
+	// Compress the backups with gzip
+	Compress bool
+
+	mutex  sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.needsRotation(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate rotate the file immediately
+func (r *RotatingFile) Rotate() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return err
+		}
+	}
+	return r.rotate()
+}
+
+// Close close the file
+func (r *RotatingFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) time() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// open the file for appending, the time of the existing file is the modification time
+func (r *RotatingFile) open() error {
+	err := os.MkdirAll(filepath.Dir(r.Path), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	r.opened = r.time()
+	if r.size > 0 {
+		r.opened = info.ModTime()
+	}
+	return nil
+}
+
+func (r *RotatingFile) needsRotation(n int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.MaxSize > 0 && r.size+n > r.MaxSize {
+		return true
+	}
+	return r.Interval > 0 && !r.time().Truncate(r.Interval).Equal(r.opened.Truncate(r.Interval))
+}
+
+// rotate rename the file to the backup, compress the backup and remove the old backups
+func (r *RotatingFile) rotate() error {
+	err := r.file.Close()
+	r.file = nil
+	if err != nil {
+		return err
+	}
+
+	backup := r.backupName()
+	err = os.Rename(r.Path, backup)
+	if err != nil {
+		return err
+	}
+	if r.Compress {
+		err = compressFile(backup)
+		if err != nil {
+			return err
+		}
+	}
+	err = r.removeBackups()
+	if err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// backupName returns the unused name of the backup with the time when the file was opened
+func (r *RotatingFile) backupName() string {
+	base := r.Path + "." + r.opened.UTC().Format(backupTimeFormat)
+	name := base
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = base + "." + strconv.Itoa(i)
+	}
+	return name
+}
+
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// backup file name with the time and the counter of the backups with the same time
+type backup struct {
+	name  string
+	time  string
+	count int
+}
+
+// parseBackup parse the `<time>[.<n>][.gz]` suffix of the backup, returns false for the other files
+func parseBackup(name, suffix string) (backup, bool) {
+	suffix = strings.TrimSuffix(suffix, ".gz")
+	if len(suffix) < len(backupTimeFormat) {
+		return backup{}, false
+	}
+	b := backup{name: name, time: suffix[:len(backupTimeFormat)]}
+	if _, err := time.Parse(backupTimeFormat, b.time); err != nil {
+		return backup{}, false
+	}
+	if rest := suffix[len(backupTimeFormat):]; len(rest) > 0 {
+		count, err := strconv.Atoi(strings.TrimPrefix(rest, "."))
+		if err != nil || rest[0] != '.' || count <= 0 {
+			return backup{}, false
+		}
+		b.count = count
+	}
+	return b, true
+}
+
+// removeBackups remove the oldest backups over the maximum
+func (r *RotatingFile) removeBackups() error {
+	if r.MaxBackups <= 0 {
+		return nil
+	}
+	items, err := filepath.Glob(r.Path + ".*")
+	if err != nil {
+		return err
+	}
+	backups := make([]backup, 0, len(items))
+	for _, item := range items {
+		if b, ok := parseBackup(item, item[len(r.Path)+1:]); ok {
+			backups = append(backups, b)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].time != backups[j].time {
+			return backups[i].time < backups[j].time
+		}
+		return backups[i].count < backups[j].count
+	})
+	for len(backups) > r.MaxBackups {
+		err = os.Remove(backups[0].name)
+		if err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// compressFile compress the file to `<name>.gz` and remove the file
+func compressFile(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	w := gzip.NewWriter(out)
+	_, err = io.Copy(w, in)
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		err = out.Close()
+	} else {
+		out.Close()
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+	in.Close()
+	return os.Remove(name)
+}
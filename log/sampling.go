@@ -0,0 +1,228 @@
+package log
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SuppressedMessage message of the entry which reports the entries suppressed by the sampling
+const SuppressedMessage = "Log entries suppressed by sampling"
+
+type sampleKey struct {
+	level    Level
+	category string
+	msg      string
+}
+
+type sampleCount struct {
+	total      int
+	suppressed int
+}
+
+// SamplingLogger decorator which writes the first entries with the same level and message
+// in each interval and then only every n-th entry. The counts of the suppressed entries are
+// reported with the warn level by the background goroutine when the interval ends and on Flush
+// and Close.
+type SamplingLogger struct {
+	target     Logger
+	interval   time.Duration
+	first      int
+	thereafter int
+	mutex      sync.Mutex
+	start      time.Time
+	counts     map[sampleKey]*sampleCount
+	now        func() time.Time
+	stop       chan struct{}
+	stopped    chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewSamplingLogger create the sampling logger which writes the first entries of each level and
+// message in the interval and then every thereafter-th entry, zero thereafter drops all the rest.
+// The interval which is not positive is one second. The goroutine which reports the suppressed
+// entries runs until Close.
+func NewSamplingLogger(target Logger, interval time.Duration, first, thereafter int) *SamplingLogger {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	return newSamplingLogger(target, interval, first, thereafter, nil, ticker.C, ticker.Stop)
+}
+
+// newSamplingLogger create the sampling logger with the clock and the ticks which end the intervals
+func newSamplingLogger(target Logger, interval time.Duration, first, thereafter int, now func() time.Time,
+	ticks <-chan time.Time, stopTicks func()) *SamplingLogger {
+	s := &SamplingLogger{
+		target:     target,
+		interval:   interval,
+		first:      first,
+		thereafter: thereafter,
+		counts:     map[sampleKey]*sampleCount{},
+		now:        now,
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go s.run(ticks, stopTicks)
+	return s
+}
+
+func (s *SamplingLogger) Trace(msg string, fields ...map[string]interface{}) {
+	s.log(TraceLevel, msg, fields)
+}
+
+func (s *SamplingLogger) Debug(msg string, fields ...map[string]interface{}) {
+	s.log(DebugLevel, msg, fields)
+}
+
+func (s *SamplingLogger) Info(msg string, fields ...map[string]interface{}) {
+	s.log(InfoLevel, msg, fields)
+}
+
+func (s *SamplingLogger) Warn(msg string, fields ...map[string]interface{}) {
+	s.log(WarnLevel, msg, fields)
+}
+
+func (s *SamplingLogger) Error(msg string, fields ...map[string]interface{}) {
+	s.log(ErrorLevel, msg, fields)
+}
+
+func (s *SamplingLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	if IsEnabled(level) {
+		s.WriteEntry(newEntry(level, msg, fields))
+	}
+}
+
+// WriteEntry writes the entry to the target logger if the entry is sampled
+func (s *SamplingLogger) WriteEntry(e *Entry) {
+	if s.sample(e) {
+		write(s.target, e)
+	}
+}
+
+// Flush report the suppressed entries and flush the target logger
+func (s *SamplingLogger) Flush() {
+	s.report(s.reset())
+	if f, ok := s.target.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close report the suppressed entries and close the target logger
+func (s *SamplingLogger) Close() error {
+	s.detach()
+	if c, ok := s.target.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// detach stop the goroutine and report the suppressed entries without closing the target logger
+func (s *SamplingLogger) detach() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.stopped
+	s.report(s.reset())
+}
+
+// run report the suppressed entries of the ended intervals
+func (s *SamplingLogger) run(ticks <-chan time.Time, stopTicks func()) {
+	defer close(s.stopped)
+	defer stopTicks()
+	for {
+		select {
+		case <-ticks:
+			s.report(s.expire())
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// expire returns the counts of the ended interval, the next entry starts the new interval
+func (s *SamplingLogger) expire() map[sampleKey]*sampleCount {
+	now := s.time()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.start.IsZero() || now.Sub(s.start) < s.interval {
+		return nil
+	}
+	counts := s.counts
+	s.counts = map[sampleKey]*sampleCount{}
+	s.start = time.Time{}
+	return counts
+}
+
+func (s *SamplingLogger) time() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// sample count the entry and returns true if the entry is written
+func (s *SamplingLogger) sample(e *Entry) bool {
+	now := s.time()
+	s.mutex.Lock()
+	var ended map[sampleKey]*sampleCount
+	if s.start.IsZero() || now.Sub(s.start) >= s.interval {
+		ended = s.counts
+		s.counts = map[sampleKey]*sampleCount{}
+		s.start = now
+	}
+
+	key := sampleKey{level: e.Level, category: e.Category, msg: e.Message}
+	count, exists := s.counts[key]
+	if !exists {
+		count = &sampleCount{}
+		s.counts[key] = count
+	}
+	count.total++
+	sampled := count.total <= s.first || (s.thereafter > 0 && (count.total-s.first)%s.thereafter == 0)
+	if !sampled {
+		count.suppressed++
+	}
+	s.mutex.Unlock()
+
+	s.report(ended)
+	return sampled
+}
+
+// reset returns the counts of the current interval and starts the new interval
+func (s *SamplingLogger) reset() map[sampleKey]*sampleCount {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	counts := s.counts
+	s.counts = map[sampleKey]*sampleCount{}
+	s.start = time.Time{}
+	return counts
+}
+
+// report writes the warn entry for each level and message with the suppressed entries,
+// the entry is written with the category of the suppressed entries if the warn level is enabled
+func (s *SamplingLogger) report(counts map[sampleKey]*sampleCount) {
+	keys := make([]sampleKey, 0, len(counts))
+	for key, count := range counts {
+		if count.suppressed > 0 && IsCategoryEnabled(key.category, WarnLevel) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		if keys[i].category != keys[j].category {
+			return keys[i].category < keys[j].category
+		}
+		return keys[i].msg < keys[j].msg
+	})
+	for _, key := range keys {
+		write(s.target, &Entry{Time: s.time(), Level: WarnLevel, Category: key.category, Message: SuppressedMessage, Fields: []map[string]interface{}{{
+			"sampled.level": key.level.String(),
+			"sampled.msg":   key.msg,
+			"suppressed":    counts[key].suppressed,
+		}}})
+	}
+}
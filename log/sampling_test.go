@@ -0,0 +1,154 @@
+package log
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	l := &entries{}
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	// the ticks never end the interval, the test moves the clock
+	s := newSamplingLogger(l, time.Second, 2, 3, func() time.Time {
+		return now
+	}, make(chan time.Time), func() {})
+
+	for i := 0; i < 10; i++ {
+		s.Warn("hot", Add("i", i))
+	}
+	s.Error("hot")
+	s.Debug("skipped")
+
+	messages := func() []interface{} {
+		result := []interface{}{}
+		for _, e := range l.items {
+			if e.Message == SuppressedMessage {
+				f := MergeFields(e.Fields...)
+				result = append(result, f["sampled.level"], f["suppressed"])
+			} else {
+				result = append(result, MergeFields(e.Fields...)["i"])
+			}
+		}
+		return result
+	}
+	// first 2 entries and then every 3rd
+	assert.Equal(t, []interface{}{0, 1, 4, 7, nil}, messages())
+
+	// the next interval reports the suppressed entries
+	l.items = nil
+	now = now.Add(time.Second)
+	s.Warn("hot", Add("i", 10))
+	assert.Equal(t, []interface{}{"warn", 6, 10}, messages())
+	assert.Equal(t, WarnLevel, l.items[0].Level)
+
+	l.items = nil
+	s.Warn("hot", Add("i", 11))
+	s.Warn("hot", Add("i", 12))
+	s.Flush()
+	assert.Equal(t, []interface{}{11, "warn", 1}, messages())
+	assert.Nil(t, s.Close())
+}
+
+func TestSamplingReportLevel(t *testing.T) {
+	t.Cleanup(func() {
+		SetLevel(InfoLevel)
+		RemoveCategoryLevel("orders")
+	})
+	l := &entries{}
+	s := newSamplingLogger(l, time.Second, 1, 0, nil, make(chan time.Time), func() {})
+	SetCategoryLevel("orders", DebugLevel)
+	for i := 0; i < 2; i++ {
+		s.WriteEntry(&Entry{Level: ErrorLevel, Message: "hot"})
+		s.WriteEntry(&Entry{Level: ErrorLevel, Category: "orders", Message: "hot"})
+	}
+
+	// the suppressed entries are reported only for the categories with the enabled warn level
+	SetLevel(ErrorLevel)
+	l.items = nil
+	s.Flush()
+	assert.Len(t, l.items, 1)
+	assert.Equal(t, SuppressedMessage, l.items[0].Message)
+	assert.Equal(t, "orders", l.items[0].Category)
+	assert.Nil(t, s.Close())
+}
+
+func TestConfigureSampling(t *testing.T) {
+	original := Log
+	l := &entries{}
+	Log = l
+	t.Cleanup(func() {
+		Log = original
+	})
+	cfg := NewConfig()
+	cfg.Sampling.Enabled = true
+	cfg.Sampling.First = 1
+	cfg.Sampling.Thereafter = 0
+	cfg.Async.Enabled = true
+	assert.Nil(t, Configure(cfg))
+	s, ok := Log.(*SamplingLogger)
+	assert.True(t, ok)
+	assert.IsType(t, &AsyncLogger{}, s.target)
+
+	Warn("hot")
+	Warn("hot")
+
+	// configure again replaces the decorators and reports the suppressed entries
+	assert.Nil(t, Configure(cfg))
+	assert.NotSame(t, s, Log)
+	assert.Same(t, l, Log.(*SamplingLogger).target.(*AsyncLogger).target)
+	assert.Len(t, l.items, 2)
+	assert.Equal(t, SuppressedMessage, l.items[1].Message)
+
+	cfg.Sampling.Enabled = false
+	cfg.Async.Enabled = false
+	assert.Nil(t, Configure(cfg))
+	assert.Same(t, l, Log)
+}
+
+type lockedEntries struct {
+	mutex sync.Mutex
+	entries
+}
+
+func (l *lockedEntries) WriteEntry(e *Entry) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries.WriteEntry(e)
+}
+
+func (l *lockedEntries) messages() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	result := []string{}
+	for _, e := range l.items {
+		result = append(result, e.Message)
+	}
+	return result
+}
+
+func TestSamplingReport(t *testing.T) {
+	l := &lockedEntries{}
+	s := NewSamplingLogger(l, 10*time.Millisecond, 1, 0)
+	s.Warn("hot")
+	s.Warn("hot")
+	assert.Eventually(t, func() bool {
+		return len(l.messages()) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"hot", SuppressedMessage}, l.messages())
+
+	assert.Nil(t, s.Close())
+	assert.Nil(t, s.Close())
+	select {
+	case <-s.stopped:
+	default:
+		assert.Fail(t, "the goroutine is running")
+	}
+
+	cfg := NewConfig()
+	cfg.Sampling.Enabled = true
+	cfg.Sampling.Interval = 0
+	assert.EqualError(t, Configure(cfg), "sampling interval must be positive: 0s")
+}
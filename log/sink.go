@@ -0,0 +1,88 @@
+package log
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink output of the log entries
+type Sink struct {
+	// Writer of the formatted entries
+	Writer io.Writer
+	// Formatter of the entries, default TextFormatter
+	Formatter Formatter
+	// Level minimum level of the entries written to the sink
+	Level Level
+}
+
+// SinkLogger writes the entries which pass the global level to all sinks which accept the level
+type SinkLogger struct {
+	mutex sync.Mutex
+	sinks []Sink
+}
+
+// NewSinkLogger create logger which writes to the sinks
+func NewSinkLogger(sinks ...Sink) *SinkLogger {
+	return &SinkLogger{sinks: sinks}
+}
+
+func (l *SinkLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(TraceLevel, msg, fields)
+}
+
+func (l *SinkLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(DebugLevel, msg, fields)
+}
+
+func (l *SinkLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(InfoLevel, msg, fields)
+}
+
+func (l *SinkLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(WarnLevel, msg, fields)
+}
+
+func (l *SinkLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(ErrorLevel, msg, fields)
+}
+
+func (l *SinkLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	if IsEnabled(level) {
+		l.WriteEntry(newEntry(level, msg, fields))
+	}
+}
+
+// WriteEntry writes the entry to the sinks without the global level check
+func (l *SinkLogger) WriteEntry(e *Entry) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, s := range l.sinks {
+		if e.Level < s.Level {
+			continue
+		}
+		formatter := s.Formatter
+		if formatter == nil {
+			formatter = TextFormatter{}
+		}
+		_, _ = s.Writer.Write(formatter.Format(e))
+	}
+}
+
+// Close close the writers of the sinks except the standard output and error
+func (l *SinkLogger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var result error
+	for _, s := range l.sinks {
+		if s.Writer == os.Stdout || s.Writer == os.Stderr {
+			continue
+		}
+		if c, ok := s.Writer.(io.Closer); ok {
+			if err := c.Close(); err != nil && result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinkLogger(t *testing.T) {
+	all := &bytes.Buffer{}
+	errs := &bytes.Buffer{}
+	l := NewSinkLogger(Sink{Writer: all}, Sink{Writer: errs, Formatter: LogfmtFormatter{}, Level: ErrorLevel})
+	l.Debug("skipped")
+	l.Info("started")
+	l.WriteEntry(&Entry{Time: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Level: ErrorLevel, Message: "failed"})
+	assert.Equal(t, " [INFO] started\n[ERROR] failed\n", all.String())
+	assert.Equal(t, "ts=2021-03-04T05:06:07Z level=error msg=failed\n", errs.String())
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	f := &RotatingFile{Path: filepath.Join(dir, "app.log"), MaxSize: 10, MaxBackups: 2, Compress: true, now: func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}}
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		_, err := f.Write([]byte(line))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, f.Close())
+
+	d, err := os.ReadFile(f.Path)
+	assert.Nil(t, err)
+	assert.Equal(t, "line 4\n", string(d))
+
+	backups, err := filepath.Glob(f.Path + ".*")
+	assert.Nil(t, err)
+	sort.Strings(backups)
+	assert.Len(t, backups, 2)
+	assert.Regexp(t, `app\.log\.20210304-\d{6}\.000\.gz$`, backups[0])
+
+	gz, err := os.Open(backups[1])
+	assert.Nil(t, err)
+	defer gz.Close()
+	r, err := gzip.NewReader(gz)
+	assert.Nil(t, err)
+	d, err = io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "line 3\n", string(d))
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2021, 3, 4, 23, 0, 0, 0, time.UTC)
+	f := &RotatingFile{Path: filepath.Join(dir, "app.log"), Interval: 24 * time.Hour, now: func() time.Time {
+		return now
+	}}
+	_, err := f.Write([]byte("day 1\n"))
+	assert.Nil(t, err)
+	now = now.Add(30 * time.Minute)
+	_, err = f.Write([]byte("day 1\n"))
+	assert.Nil(t, err)
+	now = now.Add(time.Hour)
+	_, err = f.Write([]byte("day 2\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	d, err := os.ReadFile(f.Path + ".20210304-230000.000")
+	assert.Nil(t, err)
+	assert.Equal(t, "day 1\nday 1\n", string(d))
+	d, err = os.ReadFile(f.Path)
+	assert.Nil(t, err)
+	assert.Equal(t, "day 2\n", string(d))
+}
+
+func TestRotatingFileSameTime(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	f := &RotatingFile{Path: filepath.Join(dir, "app.log"), MaxSize: 7, MaxBackups: 2, now: func() time.Time {
+		return now
+	}}
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		_, err := f.Write([]byte(line))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, f.Close())
+
+	backups, err := filepath.Glob(f.Path + ".*")
+	assert.Nil(t, err)
+	sort.Strings(backups)
+	assert.Equal(t, []string{f.Path + ".20210304-050607.000.1", f.Path + ".20210304-050607.000.2"}, backups)
+	d, err := os.ReadFile(backups[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "line 3\n", string(d))
+}
+
+func TestConfigureOutputs(t *testing.T) {
+	original := Log
+	t.Cleanup(func() {
+		Log = original
+		SetLevel(InfoLevel)
+	})
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	cfg := NewConfig()
+	cfg.Format = "json"
+	cfg.Outputs = []string{"stderr", "errors"}
+	cfg.Output["stderr"] = OutputConfig{Level: "off"}
+	cfg.Output["errors"] = OutputConfig{Type: "file", Path: path, Level: "warn", Format: "logfmt"}
+	assert.Nil(t, Configure(cfg))
+
+	Info("skipped")
+	Warn("slow", Add("ms", 10))
+	previous := Log.(*SinkLogger)
+	file := previous.sinks[1].Writer.(*RotatingFile)
+	assert.NotNil(t, file.file)
+
+	cfg.Output["errors"] = OutputConfig{Type: "file", Path: path, Format: "console"}
+	assert.Nil(t, Configure(cfg))
+	assert.Nil(t, file.file)
+	assert.Equal(t, ConsoleFormatter{}, Log.(*SinkLogger).sinks[1].Formatter)
+	assert.Nil(t, Log.(*SinkLogger).Close())
+	d, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Regexp(t, `^ts=\S+ level=warn msg=slow ms=10\n$`, string(d))
+
+	cfg.Output["errors"] = OutputConfig{Type: "file"}
+	assert.EqualError(t, Configure(cfg), "log output errors: missing path of the file")
+	cfg.Outputs = []string{"syslog"}
+	assert.EqualError(t, Configure(cfg), `log output syslog: unknown type: "syslog"`)
+}